@@ -0,0 +1,136 @@
+// Package aop 提供基于interface代理的方法拦截。
+// Go无法在运行时生成实现interface的类型，需要为每个被代理的interface注册代理工厂（RegisterProxy），
+// 代理对象将方法调用转发给Handler，由Handler依次执行拦截器（Interceptor）并最终调用原对象的方法。
+package aop
+
+import (
+	"reflect"
+)
+
+// 代理对象使用的方法调用处理器
+type Handler interface {
+	// 调用方法
+	// method：方法名称，args：方法参数（可变参数以slice形式作为最后一个参数传入）
+	// return：方法的返回值，可通过Result获得指定类型的返回值
+	Invoke(method string, args ...interface{}) []interface{}
+}
+
+// 方法调用
+type Invocation struct {
+	// bean名称
+	BeanName string
+
+	// 代理的interface类型
+	Interface reflect.Type
+
+	// 方法
+	Method reflect.Method
+
+	// 方法参数，拦截器可以修改参数
+	Args []interface{}
+
+	target       reflect.Value
+	interceptors []Interceptor
+	index        int
+}
+
+// 获得原对象
+func (inv *Invocation) Target() interface{} {
+	return inv.target.Interface()
+}
+
+// 执行下一个拦截器，所有拦截器都执行后调用原对象的方法。
+// 拦截器可以多次调用Proceed（如重试），每次都会重新执行其后的所有拦截器
+func (inv *Invocation) Proceed() []interface{} {
+	if inv.index < len(inv.interceptors) {
+		i := inv.interceptors[inv.index]
+		inv.index++
+		// 拦截器返回后恢复位置，使同一拦截器再次调用Proceed时从下一个拦截器开始执行
+		defer func() {
+			inv.index--
+		}()
+		return i.Intercept(inv)
+	}
+	return inv.call()
+}
+
+func (inv *Invocation) call() []interface{} {
+	fn := inv.target.MethodByName(inv.Method.Name)
+	ft := fn.Type()
+	in := make([]reflect.Value, len(inv.Args))
+	for i, a := range inv.Args {
+		pt := ft.In(i)
+		if a == nil {
+			in[i] = reflect.Zero(pt)
+		} else {
+			in[i] = reflect.ValueOf(a)
+		}
+	}
+	var out []reflect.Value
+	if ft.IsVariadic() {
+		out = fn.CallSlice(in)
+	} else {
+		out = fn.Call(in)
+	}
+	ret := make([]interface{}, len(out))
+	for i := range out {
+		ret[i] = out[i].Interface()
+	}
+	return ret
+}
+
+// 获得方法返回值中第i个值，值为nil时返回T的零值，如：
+// aop.Result[error](ret, 1)
+func Result[T any](results []interface{}, i int) T {
+	var zero T
+	if i >= len(results) || results[i] == nil {
+		return zero
+	}
+	return results[i].(T)
+}
+
+// 方法拦截器
+type Interceptor interface {
+	// 拦截方法调用，调用inv.Proceed()继续执行后续拦截器及原方法
+	// return：方法的返回值
+	Intercept(inv *Invocation) []interface{}
+}
+
+// 方法形式的拦截器
+type InterceptorFunc func(inv *Invocation) []interface{}
+
+func (f InterceptorFunc) Intercept(inv *Invocation) []interface{} {
+	return f(inv)
+}
+
+type handler struct {
+	beanName     string
+	t            reflect.Type
+	target       reflect.Value
+	interceptors []Interceptor
+}
+
+func newHandler(beanName string, t reflect.Type, target interface{}, interceptors []Interceptor) *handler {
+	return &handler{
+		beanName:     beanName,
+		t:            t,
+		target:       reflect.ValueOf(target),
+		interceptors: interceptors,
+	}
+}
+
+func (h *handler) Invoke(method string, args ...interface{}) []interface{} {
+	m, ok := h.t.MethodByName(method)
+	if !ok {
+		panic("aop: method " + method + " not found in " + h.t.String())
+	}
+	inv := &Invocation{
+		BeanName:     h.beanName,
+		Interface:    h.t,
+		Method:       m,
+		Args:         args,
+		target:       h.target,
+		interceptors: h.interceptors,
+	}
+	return inv.Proceed()
+}
@@ -0,0 +1,188 @@
+package aop
+
+import (
+	"errors"
+	"github.com/xfali/xlog"
+	"github.com/ydx1011/gopher-core/bean"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type greeter interface {
+	Greet(name string) (string, error)
+}
+
+type greeterProxy struct {
+	h Handler
+}
+
+func (p *greeterProxy) Greet(name string) (string, error) {
+	ret := p.h.Invoke("Greet", name)
+	return Result[string](ret, 0), Result[error](ret, 1)
+}
+
+type greeterImpl struct {
+	calls int
+	fails int
+	panic bool
+	log   *[]string
+}
+
+func (g *greeterImpl) Greet(name string) (string, error) {
+	g.calls++
+	if g.log != nil {
+		*g.log = append(*g.log, "target")
+	}
+	if g.panic {
+		panic("boom")
+	}
+	if g.calls <= g.fails {
+		return "", errors.New("greet failed")
+	}
+	return "hello " + name, nil
+}
+
+var registerOnce sync.Once
+
+func registerGreeterProxy(t *testing.T) {
+	registerOnce.Do(func() {
+		if err := RegisterProxy[greeter](func(h Handler) greeter {
+			return &greeterProxy{h: h}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func newGreeterProxy(target greeter, interceptors ...Interceptor) greeter {
+	t := reflect.TypeOf((*greeter)(nil)).Elem()
+	return &greeterProxy{h: newHandler("greeter", t, target, interceptors)}
+}
+
+func logInterceptor(log *[]string, name string) Interceptor {
+	return InterceptorFunc(func(inv *Invocation) []interface{} {
+		*log = append(*log, name+" before")
+		ret := inv.Proceed()
+		*log = append(*log, name+" after")
+		return ret
+	})
+}
+
+func TestInterceptorOrder(t *testing.T) {
+	var log []string
+	p := newGreeterProxy(&greeterImpl{log: &log}, logInterceptor(&log, "a"), logInterceptor(&log, "b"))
+	ret, err := p.Greet("gopher")
+	if err != nil || ret != "hello gopher" {
+		t.Fatalf("unexpected result %q %v", ret, err)
+	}
+	expect := "a before,b before,target,b after,a after"
+	if got := strings.Join(log, ","); got != expect {
+		t.Fatalf("expect %s, got %s", expect, got)
+	}
+}
+
+func TestInterceptorRetry(t *testing.T) {
+	retry := InterceptorFunc(func(inv *Invocation) []interface{} {
+		ret := inv.Proceed()
+		if Result[error](ret, 1) != nil {
+			ret = inv.Proceed()
+		}
+		return ret
+	})
+	downstream := 0
+	count := InterceptorFunc(func(inv *Invocation) []interface{} {
+		downstream++
+		return inv.Proceed()
+	})
+	target := &greeterImpl{fails: 1}
+	p := newGreeterProxy(target, retry, count)
+	ret, err := p.Greet("gopher")
+	if err != nil || ret != "hello gopher" {
+		t.Fatalf("unexpected result %q %v", ret, err)
+	}
+	if target.calls != 2 {
+		t.Fatalf("expect target called twice, got %d", target.calls)
+	}
+	if downstream != 2 {
+		t.Fatalf("expect downstream interceptor called twice, got %d", downstream)
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	p := newGreeterProxy(&greeterImpl{panic: true}, PanicRecovery(xlog.GetLogger()))
+	ret, err := p.Greet("gopher")
+	if ret != "" {
+		t.Fatalf("expect zero value, got %q", ret)
+	}
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expect panic converted to error, got %v", err)
+	}
+}
+
+func TestRegisterProxy(t *testing.T) {
+	registerGreeterProxy(t)
+	if err := RegisterProxy[greeter](func(h Handler) greeter {
+		return &greeterProxy{h: h}
+	}); err == nil {
+		t.Fatal("expect duplicate proxy error")
+	}
+	if err := RegisterProxy[*greeterImpl](func(h Handler) *greeterImpl {
+		return nil
+	}); err == nil {
+		t.Fatal("expect non interface proxy error")
+	}
+}
+
+func TestProxyCreatorPointcut(t *testing.T) {
+	registerGreeterProxy(t)
+	container := bean.NewContainer()
+	_ = container.RegisterByName("greetService", &greeterImpl{}, bean.SetQualifiers("traced"))
+	_ = container.RegisterByName("greetRepo", &greeterImpl{})
+
+	tests := []struct {
+		name     string
+		pointcut Pointcut
+		bean     string
+		proxied  bool
+	}{
+		{name: "name match", pointcut: NamePattern("*Service"), bean: "greetService", proxied: true},
+		{name: "name mismatch", pointcut: NamePattern("*Service"), bean: "greetRepo", proxied: false},
+		{name: "interface", pointcut: InterfaceType((*greeter)(nil)), bean: "greetRepo", proxied: true},
+		{name: "qualifier match", pointcut: Qualifier("traced"), bean: "greetService", proxied: true},
+		{name: "qualifier mismatch", pointcut: Qualifier("traced"), bean: "greetRepo", proxied: false},
+		{name: "all", pointcut: All(NamePattern("greet*"), Qualifier("traced")), bean: "greetRepo", proxied: false},
+		{name: "any", pointcut: Any(NamePattern("*Repo"), Qualifier("traced")), bean: "greetRepo", proxied: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			c := NewProxyCreator(OptAddAdvisor(tt.pointcut, InterceptorFunc(func(inv *Invocation) []interface{} {
+				calls++
+				return inv.Proceed()
+			})))
+			if err := c.Init(nil, container); err != nil {
+				t.Fatal(err)
+			}
+			target := &greeterImpl{}
+			o, err := c.AfterInitialization(tt.bean, target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.proxied {
+				if o != nil {
+					t.Fatalf("expect not proxied, got %T", o)
+				}
+				return
+			}
+			p, ok := o.(greeter)
+			if !ok {
+				t.Fatalf("expect greeter proxy, got %T", o)
+			}
+			if ret, _ := p.Greet("gopher"); ret != "hello gopher" || calls != 1 || target.calls != 1 {
+				t.Fatalf("unexpected proxy call: %q interceptor %d target %d", ret, calls, target.calls)
+			}
+		})
+	}
+}
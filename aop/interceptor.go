@@ -0,0 +1,39 @@
+package aop
+
+import (
+	"fmt"
+	"github.com/xfali/xlog"
+	"reflect"
+	"time"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// 记录方法耗时的拦截器，使用logger的Infof输出
+func LatencyLogger(logger xlog.Logger) Interceptor {
+	return InterceptorFunc(func(inv *Invocation) []interface{} {
+		now := time.Now()
+		defer func() {
+			logger.Infof("%s.%s cost: %d ms\n", inv.BeanName, inv.Method.Name, time.Since(now).Milliseconds())
+		}()
+		return inv.Proceed()
+	})
+}
+
+// 恢复方法panic的拦截器，输出错误日志后返回零值，方法最后一个返回值为error时返回包含panic信息的错误
+func PanicRecovery(logger xlog.Logger) Interceptor {
+	return InterceptorFunc(func(inv *Invocation) (ret []interface{}) {
+		defer func() {
+			if r := recover(); r != nil {
+				err := fmt.Errorf("%s.%s panic: %v ", inv.BeanName, inv.Method.Name, r)
+				logger.Errorln(err)
+				mt := inv.Method.Type
+				ret = make([]interface{}, mt.NumOut())
+				if n := mt.NumOut(); n > 0 && mt.Out(n-1) == errorType {
+					ret[n-1] = err
+				}
+			}
+		}()
+		return inv.Proceed()
+	})
+}
@@ -0,0 +1,68 @@
+package aop
+
+import (
+	"github.com/ydx1011/gopher-core/bean"
+	"path"
+	"reflect"
+)
+
+// 切点，选择需要拦截的bean
+type Pointcut interface {
+	// 判断是否拦截bean
+	// name：bean名称，t：代理的interface类型，info：bean注册时的配置信息
+	Matches(name string, t reflect.Type, info bean.RegisterInfo) bool
+}
+
+// 方法形式的切点
+type PointcutFunc func(name string, t reflect.Type, info bean.RegisterInfo) bool
+
+func (f PointcutFunc) Matches(name string, t reflect.Type, info bean.RegisterInfo) bool {
+	return f(name, t, info)
+}
+
+// 根据bean名称匹配，pattern规则同path.Match，如："*Service"
+func NamePattern(pattern string) Pointcut {
+	return PointcutFunc(func(name string, t reflect.Type, info bean.RegisterInfo) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	})
+}
+
+// 根据代理的interface类型匹配，t为interface的空指针，如：aop.InterfaceType((*Service)(nil))
+func InterfaceType(t interface{}) Pointcut {
+	it := reflect.TypeOf(t).Elem()
+	return PointcutFunc(func(name string, t reflect.Type, info bean.RegisterInfo) bool {
+		return t == it
+	})
+}
+
+// 根据bean的限定符（bean.SetQualifiers）匹配
+func Qualifier(qualifier string) Pointcut {
+	return PointcutFunc(func(name string, t reflect.Type, info bean.RegisterInfo) bool {
+		return info.HasQualifier(qualifier)
+	})
+}
+
+// 所有切点都匹配时匹配
+func All(pointcuts ...Pointcut) Pointcut {
+	return PointcutFunc(func(name string, t reflect.Type, info bean.RegisterInfo) bool {
+		for _, p := range pointcuts {
+			if !p.Matches(name, t, info) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// 任意切点匹配时匹配
+func Any(pointcuts ...Pointcut) Pointcut {
+	return PointcutFunc(func(name string, t reflect.Type, info bean.RegisterInfo) bool {
+		for _, p := range pointcuts {
+			if p.Matches(name, t, info) {
+				return true
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,136 @@
+package aop
+
+import (
+	"errors"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/yfig"
+	"reflect"
+	"sync"
+)
+
+type proxyFactory struct {
+	t      reflect.Type
+	create func(h Handler) interface{}
+}
+
+var (
+	proxyFactories []proxyFactory
+	proxyLock      sync.RWMutex
+)
+
+// 注册interface T的代理工厂，factory创建将方法调用转发给Handler的代理对象，如：
+//
+//	type serviceProxy struct{ h aop.Handler }
+//
+//	func (p *serviceProxy) Get(id int) (string, error) {
+//		ret := p.h.Invoke("Get", id)
+//		return aop.Result[string](ret, 0), aop.Result[error](ret, 1)
+//	}
+//
+//	aop.RegisterProxy[Service](func(h aop.Handler) Service { return &serviceProxy{h} })
+func RegisterProxy[T any](factory func(h Handler) T) error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Interface {
+		return errors.New("Proxy type must be interface, but get " + t.String())
+	}
+	proxyLock.Lock()
+	defer proxyLock.Unlock()
+
+	for _, f := range proxyFactories {
+		if f.t == t {
+			return errors.New("Proxy of " + t.String() + " is exists. ")
+		}
+	}
+	proxyFactories = append(proxyFactories, proxyFactory{
+		t: t,
+		create: func(h Handler) interface{} {
+			return factory(h)
+		},
+	})
+	return nil
+}
+
+func getProxyFactories() []proxyFactory {
+	proxyLock.RLock()
+	defer proxyLock.RUnlock()
+
+	return proxyFactories
+}
+
+// 拦截器与选择拦截bean的切点
+type Advisor struct {
+	Pointcut     Pointcut
+	Interceptors []Interceptor
+}
+
+// 根据Advisor为bean创建代理的BeanPostProcessor，注册到ApplicationContext后生效：
+// bean实现了已注册代理工厂的interface（按注册顺序选择第一个）且有切点匹配时，使用代理替换原对象，
+// 代理按Advisor的添加顺序执行匹配的拦截器。
+type ProxyCreator struct {
+	advisors  []Advisor
+	container bean.Container
+}
+
+type Opt func(*ProxyCreator)
+
+// 添加拦截器及其切点
+func OptAddAdvisor(pointcut Pointcut, interceptors ...Interceptor) Opt {
+	return func(creator *ProxyCreator) {
+		creator.advisors = append(creator.advisors, Advisor{
+			Pointcut:     pointcut,
+			Interceptors: interceptors,
+		})
+	}
+}
+
+func NewProxyCreator(opts ...Opt) *ProxyCreator {
+	ret := &ProxyCreator{}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+func (c *ProxyCreator) Init(conf yfig.Properties, container bean.Container) error {
+	c.container = container
+	return nil
+}
+
+func (c *ProxyCreator) Classify(o interface{}) (bool, error) {
+	return false, nil
+}
+
+func (c *ProxyCreator) Process() error {
+	return nil
+}
+
+func (c *ProxyCreator) BeanDestroy() error {
+	return nil
+}
+
+func (c *ProxyCreator) BeforeInitialization(name string, o interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func (c *ProxyCreator) AfterInitialization(name string, o interface{}) (interface{}, error) {
+	var info bean.RegisterInfo
+	if c.container != nil {
+		info, _ = bean.GetRegisterInfo(c.container, name)
+	}
+	ot := reflect.TypeOf(o)
+	for _, f := range getProxyFactories() {
+		if !ot.Implements(f.t) {
+			continue
+		}
+		var interceptors []Interceptor
+		for _, a := range c.advisors {
+			if a.Pointcut.Matches(name, f.t, info) {
+				interceptors = append(interceptors, a.Interceptors...)
+			}
+		}
+		if len(interceptors) > 0 {
+			return f.create(newHandler(name, f.t, o, interceptors)), nil
+		}
+	}
+	return nil, nil
+}
@@ -1,6 +1,7 @@
 package appcontext
 
 import (
+	"fmt"
 	"github.com/ydx1011/gopher-core/bean"
 	"github.com/ydx1011/gopher-core/processor"
 	"github.com/ydx1011/yfig"
@@ -31,6 +32,8 @@ type ApplicationContext interface {
 	AddProcessor(processor.Processor) error
 
 	// 启动应用
+	// 严格模式（gopher.application.failFast）下返回启动各阶段汇总的错误（errors.Errors，元素为*BeanError），
+	// 非严格模式下只在创建bean的方法失败（*BeanCreationError）时返回错误，其他错误输出日志
 	Start() error
 
 	// 关闭，用于资源回收
@@ -41,6 +44,63 @@ type ApplicationContext interface {
 	ApplicationEventHandler
 }
 
+// 提供配置属性的ApplicationContext（可选接口）
+type PropertiesProvider interface {
+	// 获得初始化时的配置属性，使用config.PropertySources加载时可通过config.Origin查询属性来源
+	GetProperties() yfig.Properties
+}
+
+// 提供bean容器的ApplicationContext（可选接口）
+type ContainerProvider interface {
+	// 获得bean容器
+	GetContainer() bean.Container
+}
+
+// 支持自定义bean作用域的ApplicationContext（可选接口）
+type ScopeRegistry interface {
+	// 注册自定义bean作用域，注册后可通过bean.SetScope(scope.Name())使用
+	RegisterScope(scope bean.Scope) error
+}
+
+// 支持刷新配置的ApplicationContext（可选接口）
+type Refreshable interface {
+	// 使用新的配置刷新，只能在启动完成后调用
+	// 对标记为可刷新（bean.SetRefreshable）的bean重新填充配置属性，并发布ConfigChangedEvent
+	Refresh(config yfig.Properties) error
+}
+
+// 获得ApplicationContext的配置属性，未实现PropertiesProvider时返回nil
+func GetProperties(ctx ApplicationContext) yfig.Properties {
+	if p, ok := ctx.(PropertiesProvider); ok {
+		return p.GetProperties()
+	}
+	return nil
+}
+
+// 获得ApplicationContext的bean容器，未实现ContainerProvider时返回nil
+func GetContainer(ctx ApplicationContext) bean.Container {
+	if p, ok := ctx.(ContainerProvider); ok {
+		return p.GetContainer()
+	}
+	return nil
+}
+
+// 注册自定义bean作用域，ApplicationContext未实现ScopeRegistry时返回错误
+func RegisterScope(ctx ApplicationContext, scope bean.Scope) error {
+	if r, ok := ctx.(ScopeRegistry); ok {
+		return r.RegisterScope(scope)
+	}
+	return fmt.Errorf("ApplicationContext %T not support custom scope. ", ctx)
+}
+
+// 使用新的配置刷新ApplicationContext，未实现Refreshable时返回错误
+func Refresh(ctx ApplicationContext, config yfig.Properties) error {
+	if r, ok := ctx.(Refreshable); ok {
+		return r.Refresh(config)
+	}
+	return fmt.Errorf("ApplicationContext %T not support refresh. ", ctx)
+}
+
 type ApplicationContextAware interface {
 	// 装配ApplicationContext
 	// 在bean未被注入和初始化之前调用
@@ -0,0 +1,51 @@
+package appcontext
+
+import (
+	"github.com/ydx1011/gopher-core/bean"
+	"strings"
+	"testing"
+)
+
+// 只实现ApplicationContext接口的context
+type minimalContext struct {
+	ApplicationContext
+}
+
+type tenantScope struct {
+	bean.Scope
+}
+
+func (s tenantScope) Name() string {
+	return "tenant"
+}
+
+func TestOptionalContextInterfaces(t *testing.T) {
+	tests := []struct {
+		name      string
+		wrap      func(ctx ApplicationContext) ApplicationContext
+		supported bool
+	}{
+		{name: "default", wrap: func(ctx ApplicationContext) ApplicationContext { return ctx }, supported: true},
+		{name: "minimal", wrap: func(ctx ApplicationContext) ApplicationContext { return &minimalContext{ctx} }, supported: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := newTestContext(t, nil)
+			defer inner.Close()
+			ctx := tt.wrap(inner)
+			if (GetProperties(ctx) != nil) != tt.supported {
+				t.Fatalf("expect properties %v", tt.supported)
+			}
+			if (GetContainer(ctx) != nil) != tt.supported {
+				t.Fatalf("expect container %v", tt.supported)
+			}
+			if err := RegisterScope(ctx, tenantScope{bean.NewPrototypeScope()}); (err == nil) != tt.supported {
+				t.Fatalf("expect register scope %v, got %v", tt.supported, err)
+			}
+			err := Refresh(ctx, GetProperties(inner))
+			if unsupported := err != nil && strings.Contains(err.Error(), "not support refresh"); unsupported == tt.supported {
+				t.Fatalf("expect refresh supported %v, got %v", tt.supported, err)
+			}
+		})
+	}
+}
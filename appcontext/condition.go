@@ -0,0 +1,139 @@
+package appcontext
+
+import (
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/config"
+	errors2 "github.com/ydx1011/gopher-core/errors"
+	"github.com/ydx1011/gopher-core/reflection"
+	"github.com/ydx1011/yfig"
+)
+
+// 条件注册bean的判断结果
+type ConditionOutcome struct {
+	// bean名称
+	Name string
+
+	// bean类型名称
+	Type string
+
+	// 是否满足条件（已注册）
+	Matched bool
+
+	// 各条件的判断说明
+	Reasons []string
+}
+
+// 提供条件注册判断结果的ApplicationContext
+type ConditionReporter interface {
+	// 获得所有条件注册bean的判断结果，在Start之后可用
+	GetConditionReport() []ConditionOutcome
+}
+
+type conditionalBean struct {
+	name       string
+	o          interface{}
+	opts       []bean.RegisterOpt
+	conditions []bean.Condition
+	// 已满足条件并注册到容器时的判断结果，启动失败后再次Start时不再重复注册
+	registered *ConditionOutcome
+}
+
+func (ctx *defaultApplicationContext) addConditionalBean(name string, o interface{}, opts []bean.RegisterOpt, conditions []bean.Condition) error {
+	// 提前检查对象是否能够注册
+	if _, err := bean.CreateBeanDefinition(unwrapConfigBinding(o)); err != nil {
+		return err
+	}
+	ctx.conditionLock.Lock()
+	defer ctx.conditionLock.Unlock()
+
+	ctx.conditionalBeans = append(ctx.conditionalBeans, conditionalBean{
+		name:       name,
+		o:          o,
+		opts:       opts,
+		conditions: conditions,
+	})
+	return nil
+}
+
+// 按注册顺序判断条件，满足条件的bean注册到容器中，后判断的bean能够看到先注册的bean。
+// 条件注册的bean在启动成功后才清除，启动失败后再次Start时重新判断未注册的bean
+func (ctx *defaultApplicationContext) evaluateConditions() errors2.Errors {
+	ctx.conditionLock.Lock()
+	defer ctx.conditionLock.Unlock()
+
+	var errs errors2.Errors
+	props := conditionProperties{Properties: ctx.GetProperties()}
+	ctx.conditionReport = nil
+	for i := range ctx.conditionalBeans {
+		b := &ctx.conditionalBeans[i]
+		if b.registered != nil {
+			ctx.conditionReport = append(ctx.conditionReport, *b.registered)
+			continue
+		}
+		d, err := bean.CreateBeanDefinition(unwrapConfigBinding(b.o))
+		if err != nil {
+			_ = errs.AddError(err)
+			continue
+		}
+		outcome := ConditionOutcome{
+			Name:    b.name,
+			Type:    reflection.GetTypeName(d.Type()),
+			Matched: true,
+		}
+		if outcome.Name == "" {
+			outcome.Name = d.Name()
+		}
+		for _, c := range b.conditions {
+			ok, reason := c.Matches(props, ctx.container, d)
+			outcome.Reasons = append(outcome.Reasons, reason)
+			if !ok {
+				outcome.Matched = false
+				break
+			}
+		}
+		ctx.conditionReport = append(ctx.conditionReport, outcome)
+
+		if !outcome.Matched {
+			ctx.logger.Infof("Bean [%s] skipped: %s\n", outcome.Name, outcome.Reasons[len(outcome.Reasons)-1])
+			continue
+		}
+		err = ctx.registerBean(b.name, b.o, b.opts...)
+		if err != nil {
+			_ = errs.AddError(newBeanError(PhaseCondition, outcome.Name, d.Type(), err))
+			continue
+		}
+		b.registered = &outcome
+	}
+	return errs
+}
+
+// 启动成功后清除条件注册的bean
+func (ctx *defaultApplicationContext) clearConditionalBeans() {
+	ctx.conditionLock.Lock()
+	defer ctx.conditionLock.Unlock()
+
+	ctx.conditionalBeans = nil
+}
+
+func (ctx *defaultApplicationContext) GetConditionReport() []ConditionOutcome {
+	ctx.conditionLock.Lock()
+	defer ctx.conditionLock.Unlock()
+
+	return ctx.conditionReport
+}
+
+// 将配置属性适配为bean.PropertyResolver
+type conditionProperties struct {
+	yfig.Properties
+}
+
+func (p conditionProperties) Get(key string, defaultValue string) string {
+	if p.Properties == nil {
+		return defaultValue
+	}
+	return p.Properties.Get(key, defaultValue)
+}
+
+func (p conditionProperties) ActiveProfiles() []string {
+	return config.ActiveProfiles(p.Properties)
+}
@@ -0,0 +1,137 @@
+package appcontext
+
+import (
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/yfig"
+	"testing"
+)
+
+type condService interface {
+	serve() string
+}
+
+type condImpl struct{}
+
+func (s *condImpl) serve() string { return "impl" }
+
+type condDefault struct{}
+
+func (s *condDefault) serve() string { return "default" }
+
+type condFeature struct{}
+
+type condFeatureUser struct{}
+
+func TestConditionRegistrationOrder(t *testing.T) {
+	missingService := bean.OnMissingBean((*condService)(nil))
+	tests := []struct {
+		name     string
+		enabled  string
+		register func(ctx *defaultApplicationContext)
+		expect   map[string]bool
+	}{
+		{
+			name: "default after impl",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(&condImpl{})
+				_ = ctx.RegisterBean(&condDefault{}, missingService)
+			},
+			expect: map[string]bool{"impl": true, "default": false},
+		},
+		{
+			// 无条件的bean在注册时直接加入容器，条件在启动时判断
+			name: "default before impl",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(&condDefault{}, missingService)
+				_ = ctx.RegisterBean(&condImpl{})
+			},
+			expect: map[string]bool{"impl": true, "default": false},
+		},
+		{
+			name: "default only",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(&condDefault{}, missingService)
+			},
+			expect: map[string]bool{"default": true},
+		},
+		{
+			// 后判断的bean能够看到先满足条件注册的bean
+			name:    "conditional chain",
+			enabled: "true",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(&condFeature{}, bean.OnProperty("gopher.application.feature", ""))
+				_ = ctx.RegisterBean(&condFeatureUser{}, bean.OnBean(&condFeature{}))
+			},
+			expect: map[string]bool{"feature": true, "user": true},
+		},
+		{
+			// 按注册顺序判断，先判断的bean看不到之后才满足条件的bean
+			name:    "conditional chain reversed",
+			enabled: "true",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(&condFeatureUser{}, bean.OnBean(&condFeature{}))
+				_ = ctx.RegisterBean(&condFeature{}, bean.OnProperty("gopher.application.feature", ""))
+			},
+			expect: map[string]bool{"feature": true, "user": false},
+		},
+		{
+			name:    "property disabled",
+			enabled: "false",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(&condFeature{}, bean.OnProperty("gopher.application.feature", ""))
+				_ = ctx.RegisterBean(&condFeatureUser{}, bean.OnBean(&condFeature{}))
+			},
+			expect: map[string]bool{"feature": false, "user": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t, map[string]interface{}{"feature": tt.enabled})
+			defer ctx.Close()
+			tt.register(ctx)
+			if err := ctx.Start(); err != nil {
+				t.Fatal(err)
+			}
+			found := map[string]bool{
+				"impl":    ctx.GetBeanByType(new(*condImpl)),
+				"default": ctx.GetBeanByType(new(*condDefault)),
+				"feature": ctx.GetBeanByType(new(*condFeature)),
+				"user":    ctx.GetBeanByType(new(*condFeatureUser)),
+			}
+			for name, v := range found {
+				if v != tt.expect[name] {
+					t.Fatalf("bean %s expect registered %v, got %v", name, tt.expect[name], v)
+				}
+			}
+			for _, o := range ctx.GetConditionReport() {
+				if len(o.Reasons) == 0 {
+					t.Fatalf("expect reasons in condition report: %v", o)
+				}
+			}
+		})
+	}
+}
+
+func TestConditionProfile(t *testing.T) {
+	ctx := NewDefaultApplicationContext()
+	v := yfig.Value{
+		"gopher": map[string]interface{}{
+			"application": map[string]interface{}{"bannerMode": "off"},
+			"profiles":    map[string]interface{}{"active": "dev"},
+		},
+	}
+	prop := yfig.New()
+	prop.Value = &v
+	if err := ctx.Init(prop); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	_ = ctx.RegisterBean(&condDefault{}, bean.OnProfile("dev"))
+	_ = ctx.RegisterBean(&condImpl{}, bean.OnProfile("!dev"))
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if !ctx.GetBeanByType(new(*condDefault)) || ctx.GetBeanByType(new(*condImpl)) {
+		t.Fatal("expect only the dev profile bean registered")
+	}
+}
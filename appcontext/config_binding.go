@@ -0,0 +1,44 @@
+package appcontext
+
+import (
+	"github.com/ydx1011/gopher-core/bean"
+	errors2 "github.com/ydx1011/gopher-core/errors"
+	"reflect"
+)
+
+type configBinding struct {
+	name    string
+	binding *bean.ConfigBinding
+}
+
+// 配置属性对象以其绑定的对象注册
+func unwrapConfigBinding(o interface{}) interface{} {
+	if b, ok := o.(*bean.ConfigBinding); ok {
+		return b.Target
+	}
+	return o
+}
+
+func (ctx *defaultApplicationContext) addConfigBinding(name string, b *bean.ConfigBinding) {
+	ctx.bindingLock.Lock()
+	defer ctx.bindingLock.Unlock()
+
+	ctx.configBindings = append(ctx.configBindings, configBinding{
+		name:    name,
+		binding: b,
+	})
+}
+
+// 按注册顺序绑定所有配置属性对象，在注入之前执行
+func (ctx *defaultApplicationContext) bindConfigs() errors2.Errors {
+	ctx.bindingLock.Lock()
+	defer ctx.bindingLock.Unlock()
+
+	var errs errors2.Errors
+	for _, b := range ctx.configBindings {
+		if err := b.binding.Bind(ctx.GetProperties()); err != nil {
+			_ = errs.AddError(newBeanError(PhaseBind, b.name, reflect.TypeOf(b.binding.Target), err))
+		}
+	}
+	return errs
+}
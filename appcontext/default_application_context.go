@@ -1,17 +1,23 @@
 package appcontext
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/xfali/xlog"
 	"github.com/ydx1011/gopher-core/bean"
+	errors2 "github.com/ydx1011/gopher-core/errors"
 	"github.com/ydx1011/gopher-core/injector"
 	"github.com/ydx1011/gopher-core/processor"
+	"github.com/ydx1011/gopher-core/reflection"
 	"github.com/ydx1011/gopher-core/version"
 	"github.com/ydx1011/yfig"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 const (
@@ -20,15 +26,23 @@ const (
 	statusInitialized
 )
 
+const (
+	defaultInjectWorkers = 1
+)
+
 type Opt func(*defaultApplicationContext)
 
 type defaultApplicationContext struct {
-	config      yfig.Properties
-	logger      xlog.Logger
-	container   bean.Container
-	injector    injector.Injector
-	funcHandler injector.InjectFunctionHandler
-	eventProc   ApplicationEventProcessor
+	config     yfig.Properties
+	configLock sync.RWMutex
+	logger     xlog.Logger
+	container  bean.Container
+	injector   injector.Injector
+	eventProc  ApplicationEventProcessor
+
+	// 每个bean的方法注入处理器，用于按依赖顺序进行方法注入
+	funcHandlers    map[string]injector.InjectFunctionHandler
+	funcHandlerLock sync.Mutex
 
 	ctxAwares    []ApplicationContextAware
 	ctxAwareLock sync.Mutex
@@ -36,11 +50,51 @@ type defaultApplicationContext struct {
 	processors     []processor.Processor
 	processorsLock sync.Mutex
 
+	postProcessors []processor.BeanPostProcessor
+	// 注册为bean的BeanPostProcessor，启动时先于其他bean初始化，初始化完成后才加入处理器链
+	postProcessorBeans []postProcessorBean
+	postProcessorsLock sync.Mutex
+
+	conditionalBeans []conditionalBean
+	conditionReport  []ConditionOutcome
+	conditionLock    sync.Mutex
+
+	configBindings []configBinding
+	bindingLock    sync.Mutex
+	refreshLock    sync.Mutex
+
+	injectPoints map[string][]dependencyPoint
+	pointsLock   sync.Mutex
+	graph        *dependencyGraph
+
 	appName       string
 	disableInject bool
+	lazyInit      bool
+	injectWorkers int
+	failFast      bool
 	disableEvent  bool
 	curState      int32
 
+	// 已完成初始化（BeanAfterSet）的对象，用于启动失败时回滚
+	initialized     map[*beanNode]bool
+	initializedLock sync.Mutex
+
+	// 启动报告
+	startupTime  time.Duration
+	phaseTimings []PhaseTiming
+	beanTimings  map[string]*BeanTiming
+	timingLock   sync.Mutex
+
+	// 关闭的总期限，0表示不限制
+	shutdownTimeout time.Duration
+	shutdownReport  []ShutdownTimeout
+	shutdownLock    sync.Mutex
+
+	parent          ApplicationContext
+	propagateEvents bool
+
+	failureAnalyzers []FailureAnalyzer
+
 	closeOnce sync.Once
 }
 
@@ -50,27 +104,49 @@ func NewDefaultApplicationContext(opts ...Opt) *defaultApplicationContext {
 		container: bean.NewContainer(),
 		eventProc: NewEventProcessor(),
 
-		curState: statusNone,
+		funcHandlers:  map[string]injector.InjectFunctionHandler{},
+		injectPoints:  map[string][]dependencyPoint{},
+		initialized:   map[*beanNode]bool{},
+		beanTimings:   map[string]*BeanTiming{},
+		injectWorkers: defaultInjectWorkers,
+		curState:      statusNone,
+
+		shutdownTimeout: defaultShutdownTimeout,
 	}
 	ret.injector = injector.New(injector.OptSetLogger(ret.logger))
-	ret.funcHandler = injector.NewDefaultInjectFunctionHandler(ret.logger)
 
 	for _, opt := range opts {
 		opt(ret)
 	}
 
-	ret.funcHandler.SetInjector(ret.injector)
-
 	return ret
 }
 
 // 初始化context
 func (ctx *defaultApplicationContext) Init(config yfig.Properties) (err error) {
+	ctx.configLock.Lock()
 	ctx.config = config
-	ctx.appName = ctx.config.Get("gopher.application.name", "Gopher Application")
-	ctx.disableInject = ctx.config.Get("gopher.inject.disable", "false") == "true"
+	ctx.configLock.Unlock()
+	ctx.appName = config.Get("gopher.application.name", "Gopher Application")
+	ctx.disableInject = config.Get("gopher.inject.disable", "false") == "true"
+	ctx.failFast = config.Get("gopher.application.failFast", "false") == "true"
+	ctx.lazyInit = config.Get("gopher.inject.lazy", "false") == "true"
+	workers := config.Get("gopher.inject.workers", "")
+	if workers != "" {
+		ctx.injectWorkers, err = strconv.Atoi(workers)
+		if err != nil {
+			return fmt.Errorf("gopher.inject.workers must be integer, but get %s ", workers)
+		}
+	}
+	if timeout := config.Get(KeyShutdownTimeout, ""); timeout != "" {
+		if timeout == "0" {
+			ctx.shutdownTimeout = 0
+		} else if ctx.shutdownTimeout, err = time.ParseDuration(timeout); err != nil {
+			return fmt.Errorf("%s must be duration, but get %s ", KeyShutdownTimeout, timeout)
+		}
+	}
 
-	event := ctx.config.Get("gopher.application.eventMode", "on")
+	event := config.Get("gopher.application.eventMode", "on")
 	event = strings.ToLower(event)
 	if !ctx.disableEvent {
 		ctx.disableEvent = event == "off" || event == "false"
@@ -79,8 +155,20 @@ func (ctx *defaultApplicationContext) Init(config yfig.Properties) (err error) {
 	if ctx.disableEvent && ctx.eventProc != nil {
 		ctx.eventProc = NewDisableEventProcessor()
 	}
+	if workers := config.Get(KeyEventWorkers, ""); workers != "" {
+		n, err := strconv.Atoi(workers)
+		if err != nil || n < 1 {
+			return fmt.Errorf("%s must be positive integer, but get %s ", KeyEventWorkers, workers)
+		}
+		if p, ok := ctx.eventProc.(*defaultEventProcessor); ok {
+			p.pool.setWorkers(n)
+		}
+	}
 	// Register ApplicationEventPublisher
 	ctx.container.Register(ctx.eventProc.(ApplicationEventPublisher))
+	if err := ctx.registerPublisher(); err != nil {
+		return err
+	}
 
 	return ctx.eventProc.Start()
 }
@@ -101,28 +189,60 @@ func (ctx *defaultApplicationContext) RegisterBeanByName(name string, o interfac
 	if o == nil {
 		return nil
 	}
+	// 条件注册的bean在启动时判断条件后再注册
+	if conditions := bean.GetConditions(opts...); len(conditions) > 0 {
+		return ctx.addConditionalBean(name, o, opts, conditions)
+	}
+	return ctx.registerBean(name, o, opts...)
+}
+
+func (ctx *defaultApplicationContext) registerBean(name string, o interface{}, opts ...bean.RegisterOpt) error {
 	var err error
+	binding, _ := o.(*bean.ConfigBinding)
+	o = unwrapConfigBinding(o)
+	if binding != nil {
+		if t := reflect.TypeOf(o); t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("Bind config target must be struct pointer, but get %v ", t)
+		}
+	}
+	points := parseFactoryInjectPoints(o)
 	// todo
 	o, err = injector.WrapBean(o, ctx.container, ctx.injector)
 	if err != nil {
 		return err
 	}
 
+	// 全局延迟初始化，处理器需要在启动时初始化
+	if ctx.lazyInit && !isInfrastructure(o) {
+		opts = append(opts, bean.SetLazy())
+	}
+
 	if name == "" {
-		err = ctx.container.Register(o, opts...)
-	} else {
-		err = ctx.container.RegisterByName(name, o, opts...)
+		d, err := bean.CreateBeanDefinition(o)
+		if err != nil {
+			return err
+		}
+		name = d.Name()
+		if name == "" {
+			return errors.New("Cannot get bean name. ")
+		}
 	}
+	err = ctx.container.RegisterByName(name, o, opts...)
 	if err != nil {
 		return err
 	}
+	ctx.setInstanceProcessor(name)
+	ctx.addInjectPoints(name, points, true)
+	if binding != nil {
+		ctx.addConfigBinding(name, binding)
+	}
 
 	if !ctx.disableEvent {
 		// todo
 		ctx.eventProc.AddListeners(o)
 	}
 	// 方法注入
-	err = ctx.classifyInjectFunction(o)
+	err = ctx.classifyInjectFunction(name, o)
 	if err != nil {
 		return err
 	}
@@ -138,6 +258,10 @@ func (ctx *defaultApplicationContext) RegisterBeanByName(name string, o interfac
 		}
 	}
 
+	if v, ok := o.(processor.BeanPostProcessor); ok {
+		ctx.addPostProcessorBean(name, v)
+	}
+
 	return nil
 }
 
@@ -149,6 +273,21 @@ func (ctx *defaultApplicationContext) GetBeanByType(o interface{}) bool {
 	return ctx.container.GetByType(o)
 }
 
+func (ctx *defaultApplicationContext) GetProperties() yfig.Properties {
+	ctx.configLock.RLock()
+	defer ctx.configLock.RUnlock()
+
+	return ctx.config
+}
+
+func (ctx *defaultApplicationContext) GetContainer() bean.Container {
+	return ctx.container
+}
+
+func (ctx *defaultApplicationContext) RegisterScope(scope bean.Scope) error {
+	return bean.RegisterScope(ctx.container, scope)
+}
+
 func (ctx *defaultApplicationContext) AddProcessor(p processor.Processor) error {
 	if p != nil {
 		return ctx.addProcessor(p, true)
@@ -160,25 +299,70 @@ func (ctx *defaultApplicationContext) Start() error {
 	ctx.printCtxInfo()
 	// 第一次初始化，注入所有对象
 	if atomic.CompareAndSwapInt32(&ctx.curState, statusNone, statusInitializing) {
+		begin := time.Now()
+		// Conditional registration
+		var errs errors2.Errors
+		ctx.timePhase(PhaseCondition, func() { errs = ctx.evaluateConditions() })
+		if !errs.Empty() {
+			if ctx.failFast {
+				atomic.StoreInt32(&ctx.curState, statusNone)
+				return errs
+			}
+			ctx.logErrors(errs)
+		}
+
 		// ApplicationContextAware Set.
-		ctx.notifyAware()
+		ctx.timePhase(PhaseAware, ctx.notifyAware)
 
-		// Inject Beans
-		ctx.injectAll()
-		// Processor classify
-		ctx.classifyBean()
-		// call and inject all functions
-		ctx.doFunctionInject()
-		// Notify BeanAfterSet
-		ctx.notifyBeanSet()
-		// Processor process
-		ctx.doProcess()
+		// Resolve dependencies
+		var err error
+		ctx.timePhase(PhaseResolve, func() { err = ctx.resolveDependencies() })
+		if err != nil {
+			atomic.StoreInt32(&ctx.curState, statusNone)
+			return err
+		}
+
+		phases := []struct {
+			name string
+			f    func() errors2.Errors
+		}{
+			// Bind configuration properties
+			{PhaseBind, ctx.bindConfigs},
+			// Inject, classify and initialize beans in dependency order
+			{PhaseInitialize, ctx.initializeBeans},
+			// Processor process
+			{PhaseProcess, ctx.doProcess},
+			// Start lifecycle components
+			{PhaseLifecycle, ctx.autoStartLifecycles},
+		}
+		for _, phase := range phases {
+			ctx.timePhase(phase.name, func() { errs = phase.f() })
+			if errs.Empty() {
+				continue
+			}
+			// 创建bean的方法失败时bean对象不存在，非严格模式下同样返回错误
+			if ctx.failFast || hasCreationError(errs) {
+				ctx.rollback()
+				ctx.logFailureAnalysis(errs)
+				atomic.StoreInt32(&ctx.curState, statusNone)
+				return errs
+			}
+			ctx.logErrors(errs)
+		}
 
 		// 初始化完成
 		if !atomic.CompareAndSwapInt32(&ctx.curState, statusInitializing, statusInitialized) {
 			ctx.logger.Fatal("Cannot be here!")
 		}
-
+		ctx.clearConditionalBeans()
+
+		ctx.timingLock.Lock()
+		ctx.startupTime = time.Since(begin)
+		ctx.timingLock.Unlock()
+		report := ctx.GetStartupReport()
+		ctx.logStartupReport(report)
+		ctx.exportBeans()
+		ctx.notifyStartup(report)
 		ctx.notifyStarted()
 		return nil
 	} else {
@@ -193,7 +377,18 @@ func (ctx *defaultApplicationContext) Close() (err error) {
 			ctx.logger.Errorln(err)
 		}
 		ctx.notifyStopped()
-		ctx.destroyBeans()
+		// 停止Lifecycle组件及销毁bean的总期限为gopher.application.shutdownTimeout
+		c := context.Background()
+		if ctx.shutdownTimeout > 0 {
+			var cancel context.CancelFunc
+			c, cancel = context.WithTimeout(c, ctx.shutdownTimeout)
+			defer cancel()
+		}
+		ctx.logErrors(ctx.stopLifecycles(c))
+		ctx.destroyBeans(c)
+		if report := ctx.GetShutdownReport(); len(report) > 0 {
+			ctx.logger.Warnf("%d bean(s) exceeded shutdown deadline\n", len(report))
+		}
 		ctx.notifyClosed()
 	})
 
@@ -205,8 +400,9 @@ func (ctx *defaultApplicationContext) AddListeners(listeners ...interface{}) {
 }
 
 func (ctx *defaultApplicationContext) printCtxInfo() {
-	path := ctx.config.Get("gopher.application.banner", "")
-	mode := ctx.config.Get("gopher.application.bannerMode", "")
+	conf := ctx.GetProperties()
+	path := conf.Get("gopher.application.banner", "")
+	mode := conf.Get("gopher.application.bannerMode", "")
 	mode = strings.ToLower(mode)
 	printGopherInfo(version.GopherVersion, path, mode != "off" && mode != "false")
 }
@@ -220,79 +416,367 @@ func (ctx *defaultApplicationContext) notifyAware() {
 	}
 }
 
-func (ctx *defaultApplicationContext) injectAll() {
-	if ctx.disableInject {
-		return
+func (ctx *defaultApplicationContext) resolveDependencies() error {
+	resolver, _ := ctx.injector.(injector.DependencyResolver)
+
+	ctx.pointsLock.Lock()
+	defer ctx.pointsLock.Unlock()
+
+	g, err := buildDependencyGraph(ctx.logger, ctx.container, resolver, ctx.injectPoints)
+	if err != nil {
+		return err
 	}
-	ctx.container.Scan(func(key string, value bean.Definition) bool {
-		if value.IsObject() {
-			err := ctx.injector.Inject(ctx.container, value.Interface())
-			if err != nil {
-				ctx.logger.Errorln("Inject failed: ", err)
+	ctx.graph = g
+	return nil
+}
+
+// 按依赖顺序逐个初始化bean：注入、分类（Processor.Classify）、方法注入、初始化（BeanAfterSet及BeanPostProcessor），
+// 被依赖的bean完成初始化（可能已被BeanPostProcessor替换）后才会注入到依赖它的bean中。
+// 严格模式下bean的某一步骤失败后不再执行后续步骤，依赖它的bean也不再初始化。
+// BeanPostProcessor及其直接或间接依赖的bean最先初始化（不经过BeanPostProcessor处理），
+// 完成后BeanPostProcessor才加入处理器链，对之后初始化的bean生效。
+func (ctx *defaultApplicationContext) initializeBeans() errors2.Errors {
+	// 延迟初始化的bean只有被非延迟初始化的bean依赖时才在启动时初始化，否则在第一次获取时初始化
+	eager := ctx.graph.eagerNodes()
+	for _, n := range ctx.graph.order {
+		if ld, ok := n.def.(bean.LazyDefinition); ok {
+			ctx.setLazyInitializer(n, ld)
+		}
+	}
+
+	ppBeans := ctx.getPostProcessorBeans()
+	roots := make([]*beanNode, 0, len(ppBeans))
+	for _, b := range ppBeans {
+		if n, ok := ctx.graph.byName[b.name]; ok {
+			roots = append(roots, n)
+		}
+	}
+	infra, rest := ctx.graph.split(roots)
+	for _, n := range infra.order {
+		eager[n] = true
+	}
+	errs := ctx.walkInitialize(infra, eager)
+	if ctx.failFast && !errs.Empty() {
+		return errs
+	}
+	pps := make([]processor.BeanPostProcessor, len(ppBeans))
+	for i, b := range ppBeans {
+		pps[i] = b.processor
+	}
+	ctx.setPostProcessors(pps)
+	return append(errs, ctx.walkInitialize(rest, eager)...)
+}
+
+func (ctx *defaultApplicationContext) walkInitialize(g *dependencyGraph, eager map[*beanNode]bool) errors2.Errors {
+	// 严格模式下跳过初始化失败的bean的依赖者
+	return g.walk(ctx.injectWorkers, ctx.failFast, func(n *beanNode) error {
+		var err error
+		if ld, ok := n.def.(bean.LazyDefinition); ok {
+			if !eager[n] {
+				return nil
 			}
+			err = ld.Initialize()
+		} else {
+			err = ctx.initializeBean(n)
 		}
-		return true
+		return err
 	})
 }
 
-func (ctx *defaultApplicationContext) classifyBean() {
-	ctx.container.Scan(func(key string, value bean.Definition) bool {
-		//if value.IsObject() {
-		// 必须先分类，由于ValueProcessor会在Classify将配置的属性值注入
-		ctx.classifyOneBean(value)
-		//}
-		return true
+// 依次执行bean的初始化步骤，返回errors.Errors
+func (ctx *defaultApplicationContext) initializeBean(n *beanNode) error {
+	steps := []struct {
+		phase string
+		f     func(n *beanNode) error
+	}{
+		// Inject Beans
+		{PhaseInject, ctx.injectBean},
+		// Processor classify
+		{PhaseClassify, ctx.classifyBean},
+		// call and inject all functions
+		{PhaseFunctionInject, ctx.injectFunctions},
+		// Notify BeanAfterSet
+		{PhaseAfterSet, ctx.initBean},
+	}
+	var errs errors2.Errors
+	for _, step := range steps {
+		start := time.Now()
+		err := step.f(n)
+		ctx.recordStep(n, step.phase, time.Since(start))
+		if err != nil {
+			_ = errs.AddError(err)
+			if ctx.failFast {
+				break
+			}
+		}
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// 延迟初始化的bean使用被包装的对象定义执行初始化步骤。
+// 非严格模式启动过程中的初始化错误（创建bean失败除外）与其他bean一致只输出日志；启动完成后（第一次获取时）的初始化错误会被保存，
+// 之后每次获取对象时都panic。
+func (ctx *defaultApplicationContext) setLazyInitializer(n *beanNode, ld bean.LazyDefinition) {
+	ld.SetInitializer(func(creation *bean.Creation) error {
+		inner := &beanNode{
+			name:       n.name,
+			def:        ld.Unwrap(),
+			index:      n.index,
+			deps:       n.deps,
+			dependents: n.dependents,
+			creation:   creation,
+		}
+		err := ctx.initializeBean(inner)
+		if err == nil {
+			ctx.initializedLock.Lock()
+			ctx.initialized[n] = true
+			ctx.initializedLock.Unlock()
+			return nil
+		}
+		if ctx.isInitializing() && !ctx.failFast && !hasCreationError(err.(errors2.Errors)) {
+			ctx.logErrors(err.(errors2.Errors))
+			return nil
+		}
+		return err
 	})
 }
 
-func (ctx *defaultApplicationContext) classifyOneBean(o bean.Definition) {
+func (ctx *defaultApplicationContext) injectBean(n *beanNode) error {
+	if ctx.disableInject {
+		return nil
+	}
+	return ctx.protect(PhaseInject, n, func() error {
+		if n.def.IsObject() {
+			return ctx.injector.Inject(ctx.beanContainer(n), n.def.Interface())
+		}
+		// 单例按依赖顺序创建，保证并行初始化时依赖它的对象获得的是已创建的实例
+		if d, ok := n.def.(bean.ScopedDefinition); ok && d.Scope().Name() == bean.ScopeSingleton {
+			return instantiate(ctx.beanContainer(n), n.def)
+		}
+		return nil
+	})
+}
+
+// 获得初始化bean时注入使用的容器，延迟初始化的bean携带其创建链，用于检测初始化过程中的循环依赖
+func (ctx *defaultApplicationContext) beanContainer(n *beanNode) bean.Container {
+	return bean.WithCreation(ctx.container, n.creation)
+}
+
+// 执行bean在某一阶段的处理，将错误包装为BeanError。
+// 严格模式下（gopher.application.failFast）处理过程中的panic（如必须注入的字段注入失败）也会转换为错误返回，
+// 否则输出失败分析后继续panic。
+func (ctx *defaultApplicationContext) protect(phase string, n *beanNode, f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			cause, ok := r.(error)
+			if !ok {
+				cause = fmt.Errorf("%v", r)
+			}
+			if !ctx.failFast {
+				ctx.logFailureAnalysis(newBeanError(phase, n.name, n.def.Type(), cause))
+				panic(r)
+			}
+			err = newBeanError(phase, n.name, n.def.Type(), cause)
+		}
+	}()
+	err = f()
+	if err != nil {
+		return newBeanError(phase, n.name, n.def.Type(), err)
+	}
+	return nil
+}
+
+func instantiate(c bean.Container, d bean.Definition) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			cause, ok := r.(error)
+			if !ok {
+				cause = fmt.Errorf("%v", r)
+			}
+			err = &BeanCreationError{Name: d.Name(), Cause: cause}
+		}
+	}()
+	bean.ValueOf(c, d)
+	return nil
+}
+
+func (ctx *defaultApplicationContext) logErrors(errs errors2.Errors) {
+	for _, err := range errs {
+		ctx.logger.Errorln(err)
+		for _, a := range ctx.AnalyzeFailure(err) {
+			ctx.logger.Errorf("Failure analysis:\n%s\n", a)
+		}
+	}
+}
+
+func (ctx *defaultApplicationContext) classifyBean(n *beanNode) error {
+	// 必须先分类，由于ValueProcessor会在Classify将配置的属性值注入
+	var errs errors2.Errors
+	for _, err := range ctx.classifyOneBean(n.def) {
+		_ = errs.AddError(newBeanError(PhaseClassify, n.name, n.def.Type(), err))
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// 对运行时创建的对象分类，分类过程中可能再次创建对象，因此不持有处理器锁
+func (ctx *defaultApplicationContext) classifyObject(o interface{}) errors2.Errors {
+	ctx.processorsLock.Lock()
+	processors := ctx.processors
+	ctx.processorsLock.Unlock()
+
+	var errs errors2.Errors
+	for _, processor := range processors {
+		if _, err := processor.Classify(o); err != nil {
+			_ = errs.AddError(err)
+		}
+	}
+	return errs
+}
+
+func (ctx *defaultApplicationContext) classifyOneBean(o bean.Definition) errors2.Errors {
 	ctx.processorsLock.Lock()
 	defer ctx.processorsLock.Unlock()
 
+	var errs errors2.Errors
 	for _, processor := range ctx.processors {
 		_, err := o.Classify(processor)
 		//_, err := processor.Classify(o)
 		if err != nil {
-			ctx.logger.Errorln(err)
+			_ = errs.AddError(err)
 		}
 	}
+	return errs
 }
 
-func (ctx *defaultApplicationContext) doFunctionInject() {
+func (ctx *defaultApplicationContext) injectFunctions(n *beanNode) error {
 	if ctx.disableInject {
-		return
+		return nil
 	}
-	err := ctx.funcHandler.InjectAllFunctions(ctx.container)
-	if err != nil {
-		ctx.logger.Errorln(err)
+	ctx.funcHandlerLock.Lock()
+	h, ok := ctx.funcHandlers[n.name]
+	ctx.funcHandlerLock.Unlock()
+	if !ok {
+		return nil
 	}
+	return ctx.protect(PhaseFunctionInject, n, func() error {
+		return h.InjectAllFunctions(ctx.beanContainer(n))
+	})
 }
 
-func (ctx *defaultApplicationContext) notifyBeanSet() {
-	ctx.container.Scan(func(key string, value bean.Definition) bool {
-		err := value.AfterSet()
-		if err != nil {
-			ctx.logger.Errorln(err)
+// 初始化bean，在BeanAfterSet前后依次调用BeanPostProcessor，处理器返回的对象替换原对象被注入到其他bean中
+func (ctx *defaultApplicationContext) initBean(n *beanNode) error {
+	err := ctx.protect(PhaseAfterSet, n, func() error {
+		o, ok := beanInstance(n.def)
+		if !ok {
+			return ctx.afterSet(n)
 		}
-		return true
+		exposed, replaced, err := ctx.postProcess(n.name, o, func() error {
+			return ctx.afterSet(n)
+		})
+		if err != nil || !replaced {
+			return err
+		}
+		if d, ok := n.def.(bean.ProxyDefinition); ok {
+			return d.SetProxy(exposed)
+		}
+		return fmt.Errorf("Bean definition %T not support replace by BeanPostProcessor ", n.def)
 	})
+	if err == nil {
+		ctx.initializedLock.Lock()
+		ctx.initialized[n] = true
+		ctx.initializedLock.Unlock()
+	}
+	return err
 }
 
-func (ctx *defaultApplicationContext) doProcess() {
+// 在initialize前后依次调用BeanPostProcessor，返回最终暴露的对象及是否被替换
+func (ctx *defaultApplicationContext) postProcess(name string, o interface{}, initialize func() error) (interface{}, bool, error) {
+	pps := ctx.getPostProcessors()
+	exposed, replaced := o, false
+	for _, pp := range pps {
+		if pp == o {
+			continue
+		}
+		r, err := pp.BeforeInitialization(name, exposed)
+		if err != nil {
+			return o, false, err
+		}
+		if r != nil {
+			exposed, replaced = r, true
+		}
+	}
+	if err := initialize(); err != nil {
+		return o, false, err
+	}
+	for _, pp := range pps {
+		if pp == o {
+			continue
+		}
+		r, err := pp.AfterInitialization(name, exposed)
+		if err != nil {
+			return o, false, err
+		}
+		if r != nil {
+			exposed, replaced = r, true
+		}
+	}
+	return exposed, replaced, nil
+}
+
+// 作用域不持有的对象（如多例）及启动完成后创建的对象不经过启动时的初始化步骤，
+// 由对象定义在每次创建对象后回调，完成分类（如ValueProcessor注入配置值）及BeanPostProcessor处理
+func (ctx *defaultApplicationContext) setInstanceProcessor(name string) {
+	d, ok := ctx.container.GetDefinition(name)
+	if !ok {
+		return
+	}
+	if ld, ok := d.(bean.LazyDefinition); ok {
+		d = ld.Unwrap()
+	}
+	if p, ok := d.(bean.InstanceProcessable); ok {
+		p.SetInstanceProcessor(func(o interface{}, initialize func() error) (interface{}, error) {
+			if errs := ctx.classifyObject(o); !errs.Empty() {
+				return o, errs
+			}
+			exposed, _, err := ctx.postProcess(name, o, initialize)
+			return exposed, err
+		})
+	}
+}
+
+func (ctx *defaultApplicationContext) doProcess() errors2.Errors {
 	ctx.processorsLock.Lock()
 	defer ctx.processorsLock.Unlock()
 
+	var errs errors2.Errors
 	for _, processor := range ctx.processors {
 		err := processor.Process()
-		// processor error must return
 		if err != nil {
-			ctx.logger.Fatalln(err)
+			t := reflect.TypeOf(processor)
+			_ = errs.AddError(newBeanError(PhaseProcess, reflection.GetTypeName(t), t, err))
 		}
 	}
+	return errs
 }
 
-func (ctx *defaultApplicationContext) PublishEvent(e ApplicationEvent) error {
-	return ctx.eventProc.PublishEvent(e)
+// 启动失败时按依赖的逆序销毁已完成初始化的对象
+func (ctx *defaultApplicationContext) rollback() {
+	ctx.initializedLock.Lock()
+	defer ctx.initializedLock.Unlock()
+
+	for i := len(ctx.graph.order) - 1; i >= 0; i-- {
+		n := ctx.graph.order[i]
+		if ctx.initialized[n] {
+			ctx.destroyBean(context.Background(), n.name, n.def)
+			delete(ctx.initialized, n)
+		}
+	}
 }
 
 func (ctx *defaultApplicationContext) notifyStarted() {
@@ -315,11 +799,22 @@ func (ctx *defaultApplicationContext) notifyClosed() {
 	ctx.eventProc.NotifyEvent(e)
 }
 
-func (ctx *defaultApplicationContext) destroyBeans() {
+// 超过c的期限后不再等待剩余bean的销毁
+func (ctx *defaultApplicationContext) destroyBeans(c context.Context) {
+	destroyed := map[string]bool{}
+	// 按依赖的逆序销毁，依赖其他对象的对象先销毁
+	if ctx.graph != nil {
+		for i := len(ctx.graph.order) - 1; i >= 0; i-- {
+			n := ctx.graph.order[i]
+			ctx.destroyBean(c, n.name, n.def)
+			destroyed[n.name] = true
+		}
+	}
+	// 未参与依赖分析的对象（如未启动或注入过程中缓存的对象），不包括父context中的对象
+	parentDefs := ctx.parentDefinitions()
 	ctx.container.Scan(func(key string, value bean.Definition) bool {
-		err := value.Destroy()
-		if err != nil {
-			ctx.logger.Errorln(err)
+		if !destroyed[key] && !parentDefs[value] {
+			ctx.destroyBean(c, key, value)
 		}
 		return true
 	})
@@ -349,23 +844,127 @@ func (ctx *defaultApplicationContext) addAware(aware ApplicationContextAware, wi
 func (ctx *defaultApplicationContext) addProcessor(p processor.Processor, withLock bool) error {
 	if !withLock {
 		ctx.processors = append(ctx.processors, p)
-		return p.Init(ctx.config, ctx.container)
+		return p.Init(ctx.GetProperties(), ctx.container)
 	}
 
 	ctx.processorsLock.Lock()
 	defer ctx.processorsLock.Unlock()
 
 	ctx.processors = append(ctx.processors, p)
-	return p.Init(ctx.config, ctx.container)
+	return p.Init(ctx.GetProperties(), ctx.container)
+}
+
+type postProcessorBean struct {
+	name      string
+	processor processor.BeanPostProcessor
+}
+
+// 启动完成后注册的BeanPostProcessor直接加入处理器链，否则在启动时初始化后加入
+func (ctx *defaultApplicationContext) addPostProcessorBean(name string, p processor.BeanPostProcessor) {
+	ctx.postProcessorsLock.Lock()
+	defer ctx.postProcessorsLock.Unlock()
+
+	if atomic.LoadInt32(&ctx.curState) == statusInitialized {
+		ctx.postProcessors = append(ctx.postProcessors, p)
+		return
+	}
+	ctx.postProcessorBeans = append(ctx.postProcessorBeans, postProcessorBean{name: name, processor: p})
+}
+
+func (ctx *defaultApplicationContext) getPostProcessorBeans() []postProcessorBean {
+	ctx.postProcessorsLock.Lock()
+	defer ctx.postProcessorsLock.Unlock()
+
+	return ctx.postProcessorBeans
+}
+
+func (ctx *defaultApplicationContext) setPostProcessors(pps []processor.BeanPostProcessor) {
+	ctx.postProcessorsLock.Lock()
+	defer ctx.postProcessorsLock.Unlock()
+
+	ctx.postProcessors = pps
 }
 
-func (ctx *defaultApplicationContext) classifyInjectFunction(o interface{}) error {
+func (ctx *defaultApplicationContext) getPostProcessors() []processor.BeanPostProcessor {
+	ctx.postProcessorsLock.Lock()
+	defer ctx.postProcessorsLock.Unlock()
+
+	return ctx.postProcessors
+}
+
+func (ctx *defaultApplicationContext) classifyInjectFunction(name string, o interface{}) error {
 	if v, ok := o.(injector.InjectFunction); ok {
-		return v.RegisterFunction(ctx.funcHandler)
+		return v.RegisterFunction(&injectFunctionRecorder{
+			registry: ctx.getFuncHandler(name),
+			record: func(points []injector.InjectPoint) {
+				ctx.addInjectPoints(name, points, false)
+			},
+		})
+	}
+	return nil
+}
+
+func (ctx *defaultApplicationContext) getFuncHandler(name string) injector.InjectFunctionHandler {
+	ctx.funcHandlerLock.Lock()
+	defer ctx.funcHandlerLock.Unlock()
+
+	if h, ok := ctx.funcHandlers[name]; ok {
+		return h
+	}
+	h := injector.NewDefaultInjectFunctionHandler(ctx.logger)
+	h.SetInjector(ctx.injector)
+	ctx.funcHandlers[name] = h
+	return h
+}
+
+func (ctx *defaultApplicationContext) addInjectPoints(name string, points []injector.InjectPoint, construct bool) {
+	if len(points) == 0 {
+		return
+	}
+	ctx.pointsLock.Lock()
+	defer ctx.pointsLock.Unlock()
+
+	for _, p := range points {
+		ctx.injectPoints[name] = append(ctx.injectPoints[name], dependencyPoint{
+			InjectPoint: p,
+			construct:   construct,
+		})
+	}
+}
+
+// 记录方法注入的注入点，用于分析依赖关系
+type injectFunctionRecorder struct {
+	registry injector.InjectFunctionRegistry
+	record   func(points []injector.InjectPoint)
+}
+
+func (r *injectFunctionRecorder) RegisterInjectFunction(function interface{}, names ...string) error {
+	err := r.registry.RegisterInjectFunction(function, names...)
+	if err == nil {
+		r.record(injector.ParseFunctionInjectPoints(function, names))
+	}
+	return err
+}
+
+// 解析创建bean方法参数的注入点
+func parseFactoryInjectPoints(o interface{}) []injector.InjectPoint {
+	if b, ok := o.(bean.CustomBeanFactory); ok {
+		return injector.ParseFunctionInjectPoints(b.BeanFactory(), b.InjectNames())
+	}
+	if reflect.TypeOf(o).Kind() == reflect.Func {
+		return injector.ParseFunctionInjectPoints(o, nil)
 	}
 	return nil
 }
 
+func isInfrastructure(o interface{}) bool {
+	switch o.(type) {
+	case processor.Processor, processor.BeanPostProcessor:
+		return true
+	}
+	return false
+}
+
 func (ctx *defaultApplicationContext) isInitializing() bool {
 	return atomic.LoadInt32(&ctx.curState) == statusInitializing
 }
@@ -16,9 +16,18 @@ var eventType = reflect.TypeOf((*ApplicationEvent)(nil)).Elem()
 type defaultEventProcessor struct {
 	logger xlog.Logger
 
-	listeners    []ApplicationEventListener
+	listeners    listenerTable
 	listenerLock sync.Mutex
 
+	// 异步监听器共享的协程池及监听器独占的协程池
+	workers   int
+	pool      *eventPool
+	dedicated []*eventPool
+	// 已关闭，关闭后创建的独占协程池同样处于关闭状态，Start时与其他协程池一起重新打开
+	closed bool
+
+	errorHandler ErrorHandler
+
 	eventBufSize int
 	eventChan    chan ApplicationEvent
 
@@ -36,18 +45,38 @@ func NewEventProcessor(opts ...EventProcessorOpt) *defaultEventProcessor {
 		logger:              xlog.GetLogger(),
 		eventBufSize:        defaultEventBufferSize,
 		consumerListenerFac: defaultConsumerListenerFac,
+		workers:             defaultEventWorkers(),
 	}
 
 	for _, opt := range opts {
 		opt(ret)
 	}
+	ret.pool = newEventPool(ret.workers)
+	if ret.errorHandler == nil {
+		ret.errorHandler = LogErrorHandler(ret.logger)
+	}
 	return ret
 }
+
+// 设置异步监听器（DeliveryAsync）共享协程池的协程数
+func OptSetEventWorkers(workers int) EventProcessorOpt {
+	return func(processor *defaultEventProcessor) {
+		processor.workers = workers
+	}
+}
+
 func (h *defaultEventProcessor) Start() error {
 	h.eventChan = make(chan ApplicationEvent, h.eventBufSize)
 	h.stopChan = make(chan struct{})
 	h.finishChan = make(chan struct{})
 	h.closeOnce = sync.Once{}
+	h.listenerLock.Lock()
+	h.closed = false
+	h.pool.reopen()
+	for _, p := range h.dedicated {
+		p.reopen()
+	}
+	h.listenerLock.Unlock()
 
 	go h.eventLoop()
 
@@ -84,6 +113,15 @@ func (h *defaultEventProcessor) Close() (err error) {
 		close(h.stopChan)
 		//wait for eventLoop exit
 		<-h.finishChan
+		// 等待异步监听器处理完已分发的事件
+		h.listenerLock.Lock()
+		h.closed = true
+		dedicated := h.dedicated
+		h.listenerLock.Unlock()
+		h.pool.close()
+		for _, p := range dedicated {
+			p.close()
+		}
 		h.logger.Infoln("Event Processor closed.")
 	})
 
@@ -113,28 +151,66 @@ func (h *defaultEventProcessor) NotifyEvent(e ApplicationEvent) error {
 	return nil
 }
 
+// 同步监听器在当前协程中依次调用，异步监听器分发到协程池，调用监听器时不持有锁
 func (h *defaultEventProcessor) notifyEvent(e ApplicationEvent) {
 	h.listenerLock.Lock()
-	defer h.listenerLock.Unlock()
-
-	for _, v := range h.listeners {
-		v.OnApplicationEvent(e)
+	entries := h.listeners.match(e)
+	h.listenerLock.Unlock()
+
+	for _, v := range entries {
+		if v.async != nil {
+			v.async.deliver(e)
+		} else {
+			h.invokeListener(v.listener, e)
+		}
 	}
 }
 
 func (h *defaultEventProcessor) processListener(o interface{}) {
+	var delivery DeliveryListener
+	if dl, ok := o.(*deliveryListener); ok {
+		delivery = dl
+		o = dl.listener
+	}
 	l := h.classifyListenerInterface(o)
-	if l != nil {
-		h.addListener(l, true)
+	if l == nil {
+		var err error
+		l, err = h.parseListener(o)
+		if err != nil {
+			//ctx.logger.Errorln(err)
+			return
+		}
+	}
+	if l == nil {
 		return
 	}
+	if delivery == nil {
+		delivery, _ = l.(DeliveryListener)
+	}
+	h.addListener(l, delivery, true)
+}
 
-	l, err := h.parseListener(o)
-	if err != nil {
-		//ctx.logger.Errorln(err)
-	} else if l != nil {
-		h.addListener(l, true)
+// 根据监听器的分发方式创建异步分发器，同步分发返回nil
+func (h *defaultEventProcessor) createDeliverer(l ApplicationEventListener, delivery DeliveryListener) *asyncDeliverer {
+	if delivery == nil {
+		return nil
 	}
+	switch delivery.DeliveryMode() {
+	case DeliveryAsync:
+		return &asyncDeliverer{listener: l, pool: h.pool, serial: true, invoke: h.invokeListener}
+	case DeliveryDedicated:
+		workers := 1
+		if dl, ok := delivery.(*deliveryListener); ok && dl.workers > 1 {
+			workers = dl.workers
+		}
+		p := newEventPool(workers)
+		if h.closed {
+			p.close()
+		}
+		h.dedicated = append(h.dedicated, p)
+		return &asyncDeliverer{listener: l, pool: p, serial: workers == 1, invoke: h.invokeListener}
+	}
+	return nil
 }
 
 func (h *defaultEventProcessor) parseListener(o interface{}) (ApplicationEventListener, error) {
@@ -166,16 +242,16 @@ func (h *defaultEventProcessor) classifyListenerInterface(o interface{}) Applica
 	return nil
 }
 
-func (h *defaultEventProcessor) addListener(l ApplicationEventListener, withLock bool) {
+func (h *defaultEventProcessor) addListener(l ApplicationEventListener, delivery DeliveryListener, withLock bool) {
 	if !withLock {
-		h.listeners = append(h.listeners, l)
+		h.listeners.add(l, h.createDeliverer(l, delivery))
 		return
 	}
 
 	h.listenerLock.Lock()
 	defer h.listenerLock.Unlock()
 
-	h.listeners = append(h.listeners, l)
+	h.listeners.add(l, h.createDeliverer(l, delivery))
 }
 
 type dummyEventProc struct{}
@@ -203,6 +279,12 @@ func (ep *eventProcessor) OnApplicationEvent(e ApplicationEvent) {
 	}
 }
 
+// 所有方法接收同一具体事件类型时返回该类型，事件处理器在添加监听器时据此按类型索引，否则返回nil。
+// 注意：监听器添加到事件处理器后再注册的方法不会更新索引
+func (ep *eventProcessor) EventType() reflect.Type {
+	return invokersType(ep.invokers)
+}
+
 func NewDisableEventProcessor() *dummyEventProc {
 	return &dummyEventProc{}
 }
@@ -246,6 +328,11 @@ func NewPayloadApplicationEvent(payload interface{}) *PayloadApplicationEvent {
 	return &e
 }
 
+// 获得事件的payload
+func (e *PayloadApplicationEvent) GetPayload() interface{} {
+	return e.payload
+}
+
 func (l *PayloadEventListener) OnApplicationEvent(e ApplicationEvent) {
 	if len(l.invokers) > 0 {
 		if pe, ok := e.(*PayloadApplicationEvent); ok {
@@ -256,6 +343,11 @@ func (l *PayloadEventListener) OnApplicationEvent(e ApplicationEvent) {
 	}
 }
 
+// 所有方法接收同一具体payload类型时返回该类型，否则返回nil
+func (l *PayloadEventListener) PayloadType() reflect.Type {
+	return invokersType(l.invokers)
+}
+
 type ConsumerInvoker interface {
 	// 消费
 	Invoke(data interface{}) bool
@@ -264,6 +356,27 @@ type ConsumerInvoker interface {
 	ResolveConsumer(consumer interface{}) error
 }
 
+func (invoker *consumerInvoker) consumeType() reflect.Type {
+	return invoker.et
+}
+
+// 获得invokers共同消费的类型，存在不同类型或无法获得类型时返回nil
+func invokersType(invokers []ConsumerInvoker) reflect.Type {
+	var ret reflect.Type
+	for _, invoker := range invokers {
+		v, ok := invoker.(interface{ consumeType() reflect.Type })
+		if !ok {
+			return nil
+		}
+		t := v.consumeType()
+		if t == nil || (ret != nil && t != ret) {
+			return nil
+		}
+		ret = t
+	}
+	return ret
+}
+
 func (invoker *consumerInvoker) Invoke(data interface{}) bool {
 	t := reflect.TypeOf(data)
 	if t.AssignableTo(invoker.et) {
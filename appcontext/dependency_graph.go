@@ -0,0 +1,390 @@
+package appcontext
+
+import (
+	"container/heap"
+	"fmt"
+	"github.com/xfali/xlog"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/errors"
+	"github.com/ydx1011/gopher-core/injector"
+	"strings"
+	"sync"
+)
+
+// 对象注册时记录的注入点
+type dependencyPoint struct {
+	injector.InjectPoint
+
+	// 是否为构造依赖（创建bean方法的参数），构造依赖的循环无法被打破
+	construct bool
+}
+
+type dependency struct {
+	node      *beanNode
+	construct bool
+}
+
+type beanNode struct {
+	name       string
+	def        bean.Definition
+	index      int
+	deps       []dependency
+	dependents []*beanNode
+	// 延迟初始化的bean初始化时所在的创建链
+	creation *bean.Creation
+}
+
+type dependencyGraph struct {
+	nodes  []*beanNode
+	byName map[string]*beanNode
+	// 拓扑排序后的初始化顺序，被依赖的对象在前
+	order []*beanNode
+}
+
+// 根据容器中已注册的对象构建依赖图：
+// 1、tag注入及方法注入（InjectFunction）的依赖为普通依赖，出现循环时输出警告并按注册顺序打破循环；
+// 2、创建bean方法参数的依赖为构造依赖，完全由构造依赖组成的循环无法创建对象，返回包含完整循环路径的错误。
+func buildDependencyGraph(logger xlog.Logger, c bean.Container, resolver injector.DependencyResolver,
+	points map[string][]dependencyPoint) (*dependencyGraph, error) {
+	g := &dependencyGraph{
+		byName: map[string]*beanNode{},
+	}
+	defNodes := map[bean.Definition]*beanNode{}
+	c.Scan(func(key string, value bean.Definition) bool {
+		if n, ok := defNodes[value]; ok {
+			g.byName[key] = n
+			return true
+		}
+		n := &beanNode{
+			name:  key,
+			def:   value,
+			index: len(g.nodes),
+		}
+		defNodes[value] = n
+		g.byName[key] = n
+		g.nodes = append(g.nodes, n)
+		return true
+	})
+
+	for _, n := range g.nodes {
+		ps := points[n.name]
+		if resolver != nil && n.def.IsObject() {
+			for _, p := range resolver.ParseInjectPoints(n.def.Interface()) {
+				ps = append(ps, dependencyPoint{InjectPoint: p})
+			}
+		}
+		for _, p := range ps {
+			if resolver == nil {
+				break
+			}
+			for _, key := range resolver.ResolveInjectPoint(c, p.InjectPoint) {
+				if dep, ok := g.byName[key]; ok && dep != n {
+					n.addDependency(dep, p.construct)
+				}
+			}
+		}
+	}
+
+	if cycle := g.findCycle(func(d dependency) bool { return d.construct }); cycle != nil {
+		return nil, fmt.Errorf("Circular dependency: %s ", formatCycle(cycle))
+	}
+	g.sort(logger)
+	return g, nil
+}
+
+func (n *beanNode) addDependency(dep *beanNode, construct bool) {
+	for i := range n.deps {
+		if n.deps[i].node == dep {
+			n.deps[i].construct = n.deps[i].construct || construct
+			return
+		}
+	}
+	n.deps = append(n.deps, dependency{node: dep, construct: construct})
+	dep.dependents = append(dep.dependents, n)
+}
+
+// 查找只经过filter所接受的依赖组成的循环，未找到返回nil
+func (g *dependencyGraph) findCycle(filter func(d dependency) bool) []*beanNode {
+	const (
+		white = iota
+		gray
+		black
+	)
+	color := make(map[*beanNode]int, len(g.nodes))
+	var stack []*beanNode
+	var visit func(n *beanNode) []*beanNode
+	visit = func(n *beanNode) []*beanNode {
+		color[n] = gray
+		stack = append(stack, n)
+		for _, d := range n.deps {
+			if !filter(d) {
+				continue
+			}
+			switch color[d.node] {
+			case gray:
+				for i := range stack {
+					if stack[i] == d.node {
+						cycle := append([]*beanNode{}, stack[i:]...)
+						return append(cycle, d.node)
+					}
+				}
+			case white:
+				if cycle := visit(d.node); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		color[n] = black
+		return nil
+	}
+	for _, n := range g.nodes {
+		if color[n] == white {
+			if cycle := visit(n); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// 拓扑排序（Kahn算法），无依赖关系的对象保持注册顺序（bean.SetOrder）。
+// 存在普通依赖的循环时选择注册顺序最靠前的对象打破循环，排序完成后统一输出一次警告
+func (g *dependencyGraph) sort(logger xlog.Logger) {
+	pending := make(map[*beanNode]int, len(g.nodes))
+	ready := &nodeHeap{}
+	for _, n := range g.nodes {
+		pending[n] = len(n.deps)
+		if len(n.deps) == 0 {
+			heap.Push(ready, n)
+		}
+	}
+	g.order = make([]*beanNode, 0, len(g.nodes))
+	var cycles []string
+	for len(pending) > 0 {
+		var next *beanNode
+		if ready.Len() > 0 {
+			next = heap.Pop(ready).(*beanNode)
+		} else {
+			// 存在普通依赖的循环，选择注册顺序最靠前的对象打破循环
+			for n := range pending {
+				if next == nil || n.index < next.index {
+					next = n
+				}
+			}
+			cycle := g.findCycle(func(d dependency) bool {
+				_, ok := pending[d.node]
+				return ok
+			})
+			if cycle != nil {
+				cycles = append(cycles, formatCycle(cycle))
+			}
+		}
+		delete(pending, next)
+		g.order = append(g.order, next)
+		for _, d := range next.dependents {
+			if c, ok := pending[d]; ok {
+				pending[d] = c - 1
+				if c == 1 {
+					heap.Push(ready, d)
+				}
+			}
+		}
+	}
+	if len(cycles) > 0 {
+		logger.Warnf("Circular dependency: %s , initialization order is not guaranteed. ", strings.Join(cycles, "; "))
+	}
+}
+
+// 按注册顺序排列的待处理对象
+type nodeHeap []*beanNode
+
+func (h nodeHeap) Len() int           { return len(h) }
+func (h nodeHeap) Less(i, j int) bool { return h[i].index < h[j].index }
+func (h nodeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *nodeHeap) Push(x interface{}) {
+	*h = append(*h, x.(*beanNode))
+}
+
+func (h *nodeHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// 获得启动时需要初始化的对象：非延迟初始化的对象及其直接或间接依赖的对象
+func (g *dependencyGraph) eagerNodes() map[*beanNode]bool {
+	ret := make(map[*beanNode]bool, len(g.nodes))
+	var visit func(n *beanNode)
+	visit = func(n *beanNode) {
+		if ret[n] {
+			return
+		}
+		ret[n] = true
+		for _, d := range n.deps {
+			visit(d.node)
+		}
+	}
+	for _, n := range g.nodes {
+		if _, ok := n.def.(bean.LazyDefinition); !ok {
+			visit(n)
+		}
+	}
+	return ret
+}
+
+// 将对象分为roots及其直接或间接依赖的对象组成的子图，以及其他对象组成的子图，子图中的顺序与原图一致
+func (g *dependencyGraph) split(roots []*beanNode) (*dependencyGraph, *dependencyGraph) {
+	in := make(map[*beanNode]bool, len(roots))
+	var visit func(n *beanNode)
+	visit = func(n *beanNode) {
+		if in[n] {
+			return
+		}
+		in[n] = true
+		for _, d := range n.deps {
+			visit(d.node)
+		}
+	}
+	for _, n := range roots {
+		visit(n)
+	}
+	first := &dependencyGraph{nodes: g.nodes, byName: g.byName}
+	second := &dependencyGraph{nodes: g.nodes, byName: g.byName}
+	for _, n := range g.order {
+		if in[n] {
+			first.order = append(first.order, n)
+		} else {
+			second.order = append(second.order, n)
+		}
+	}
+	return first, second
+}
+
+func formatCycle(cycle []*beanNode) string {
+	names := make([]string, len(cycle))
+	for i, n := range cycle {
+		names[i] = n.name
+	}
+	return strings.Join(names, " -> ")
+}
+
+// 按依赖顺序处理所有对象，被依赖的对象处理完成后才会处理依赖它的对象。
+// workers大于1时使用协程池并行处理没有依赖关系的对象，否则按拓扑顺序依次处理。
+// f发生panic时跳过直接或间接依赖该对象的对象，其他对象处理完成后在调用方协程中重新抛出panic；
+// skipOnError为true时f返回错误的对象同样跳过其依赖者。
+// 返回的错误按拓扑顺序排列，与并行处理的完成顺序无关，f返回的errors.Errors会被展开。
+func (g *dependencyGraph) walk(workers int, skipOnError bool, f func(n *beanNode) error) errors.Errors {
+	size := len(g.order)
+	results := make([]error, size)
+	panics := make([]interface{}, size)
+	pos := make(map[*beanNode]int, size)
+	for i, n := range g.order {
+		pos[n] = i
+	}
+	// 失败（或panic）的对象及其依赖者，依赖者在其依赖全部处理后才会处理，读写由调用方保证顺序
+	failed := make([]bool, size)
+	run := func(i int) {
+		defer func() {
+			if r := recover(); r != nil {
+				panics[i] = r
+				failed[i] = true
+			}
+		}()
+		n := g.order[i]
+		for _, d := range n.deps {
+			if j, ok := pos[d.node]; ok && j < i && failed[j] {
+				failed[i] = true
+				return
+			}
+		}
+		results[i] = f(n)
+		if skipOnError && results[i] != nil {
+			failed[i] = true
+		}
+	}
+
+	if workers <= 1 || size <= 1 {
+		for i := range g.order {
+			run(i)
+		}
+	} else {
+		g.walkParallel(workers, pos, run)
+	}
+
+	// 与调用方协程中直接调用f保持一致，重新抛出第一个panic
+	for _, p := range panics {
+		if p != nil {
+			panic(p)
+		}
+	}
+	var errs errors.Errors
+	for _, err := range results {
+		if es, ok := err.(errors.Errors); ok {
+			errs = append(errs, es...)
+		} else if err != nil {
+			_ = errs.AddError(err)
+		}
+	}
+	return errs
+}
+
+func (g *dependencyGraph) walkParallel(workers int, pos map[*beanNode]int, run func(i int)) {
+	size := len(g.order)
+	// 只统计拓扑顺序在前的依赖，被打破的循环依赖及不在本次处理范围内（已处理）的依赖不参与等待
+	pending := make([]int, size)
+	for i, n := range g.order {
+		for _, d := range n.deps {
+			if j, ok := pos[d.node]; ok && j < i {
+				pending[i]++
+			}
+		}
+	}
+
+	ready := make(chan int, size)
+	for i := range pending {
+		if pending[i] == 0 {
+			ready <- i
+		}
+	}
+
+	var (
+		lock sync.Mutex
+		done int
+		wg   sync.WaitGroup
+	)
+	finish := func(i int) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		for _, d := range g.order[i].dependents {
+			if j, ok := pos[d]; ok && j > i {
+				pending[j]--
+				if pending[j] == 0 {
+					ready <- j
+				}
+			}
+		}
+		done++
+		if done == size {
+			close(ready)
+		}
+	}
+
+	if workers > size {
+		workers = size
+	}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range ready {
+				run(i)
+				finish(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,288 @@
+package appcontext
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xfali/xlog"
+	errors2 "github.com/ydx1011/gopher-core/errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type orderRecorder struct {
+	lock  sync.Mutex
+	names []string
+}
+
+func (r *orderRecorder) add(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.names = append(r.names, name)
+}
+
+func (r *orderRecorder) index(name string) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i, n := range r.names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+type orderRepo struct {
+	rec *orderRecorder
+}
+
+func (b *orderRepo) BeanAfterSet() error {
+	b.rec.add("repo")
+	return nil
+}
+
+type orderService struct {
+	Repo *orderRepo `inject:""`
+	rec  *orderRecorder
+}
+
+func (b *orderService) BeanAfterSet() error {
+	b.rec.add("service")
+	return nil
+}
+
+type orderController struct {
+	Service *orderService `inject:""`
+	rec     *orderRecorder
+}
+
+func (b *orderController) BeanAfterSet() error {
+	b.rec.add("controller")
+	return nil
+}
+
+type cycleA struct{}
+
+type cycleB struct{}
+
+func TestInitializeInDependencyOrder(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			ctx := newTestContext(t, nil)
+			defer ctx.Close()
+			ctx.injectWorkers = workers
+			rec := &orderRecorder{}
+			// 注册顺序与依赖顺序相反
+			_ = ctx.RegisterBean(&orderController{rec: rec})
+			_ = ctx.RegisterBean(&orderService{rec: rec})
+			_ = ctx.RegisterBean(&orderRepo{rec: rec})
+			if err := ctx.Start(); err != nil {
+				t.Fatal(err)
+			}
+			deps := [][2]string{{"repo", "service"}, {"service", "controller"}}
+			for _, d := range deps {
+				if rec.index(d[0]) < 0 || rec.index(d[0]) > rec.index(d[1]) {
+					t.Fatalf("expect %s initialized before %s, got %v", d[0], d[1], rec.names)
+				}
+			}
+		})
+	}
+}
+
+func TestConstructionCycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(ctx *defaultApplicationContext)
+		cycle    bool
+	}{
+		{
+			name: "function parameters",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(func(b *cycleB) *cycleA { return &cycleA{} })
+				_ = ctx.RegisterBean(func(a *cycleA) *cycleB { return &cycleB{} })
+			},
+			cycle: true,
+		},
+		{
+			name: "tag injection",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(&cycleTagA{})
+				_ = ctx.RegisterBean(&cycleTagB{})
+			},
+			cycle: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t, nil)
+			defer ctx.Close()
+			tt.register(ctx)
+			err := ctx.Start()
+			if !tt.cycle {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "Circular dependency") {
+				t.Fatalf("expect circular dependency error, got %v", err)
+			}
+			// 错误包含完整的循环路径
+			if strings.Count(err.Error(), "->") != 2 {
+				t.Fatalf("expect full cycle path, got %v", err)
+			}
+		})
+	}
+}
+
+type cycleTagA struct {
+	B *cycleTagB `inject:""`
+}
+
+type cycleTagB struct {
+	A *cycleTagA `inject:""`
+}
+
+// 并行处理时错误按拓扑顺序排列，与完成顺序无关
+func TestWalkErrorOrder(t *testing.T) {
+	// a <- b <- c，d、e无依赖
+	names := []string{"a", "b", "c", "d", "e"}
+	nodes := make([]*beanNode, len(names))
+	for i, name := range names {
+		nodes[i] = &beanNode{name: name, index: i}
+	}
+	nodes[1].addDependency(nodes[0], false)
+	nodes[2].addDependency(nodes[1], false)
+	g := &dependencyGraph{nodes: nodes, byName: map[string]*beanNode{}}
+	g.sort(xlog.GetLogger())
+
+	tests := []struct {
+		name    string
+		workers int
+		fail    map[string]bool
+		expect  []string
+	}{
+		{name: "sequential", workers: 1, fail: map[string]bool{"e": true, "b": true}, expect: []string{"b", "e"}},
+		{name: "parallel", workers: 4, fail: map[string]bool{"e": true, "b": true, "d": true}, expect: []string{"b", "d", "e"}},
+		{name: "parallel nested errors", workers: 4, fail: map[string]bool{"c": true, "a": true}, expect: []string{"a", "a", "c", "c"}},
+		{name: "no error", workers: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lock sync.Mutex
+			done := map[string]bool{}
+			errs := g.walk(tt.workers, false, func(n *beanNode) error {
+				lock.Lock()
+				defer lock.Unlock()
+				for _, d := range n.deps {
+					if !done[d.node.name] {
+						t.Errorf("%s processed before its dependency %s", n.name, d.node.name)
+					}
+				}
+				done[n.name] = true
+				if !tt.fail[n.name] {
+					return nil
+				}
+				if tt.name == "parallel nested errors" {
+					// errors.Errors会被展开
+					return errors2.Errors{errors.New(n.name), errors.New(n.name)}
+				}
+				return errors.New(n.name)
+			})
+			got := make([]string, len(errs))
+			for i, err := range errs {
+				got[i] = err.Error()
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expect, ",") {
+				t.Fatalf("expect errors %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+// 失败或panic的对象的依赖者被跳过，其他对象继续处理
+func TestWalkSkipsDependents(t *testing.T) {
+	// a <- b <- c，d、e无依赖
+	names := []string{"a", "b", "c", "d", "e"}
+	nodes := make([]*beanNode, len(names))
+	for i, name := range names {
+		nodes[i] = &beanNode{name: name, index: i}
+	}
+	nodes[1].addDependency(nodes[0], false)
+	nodes[2].addDependency(nodes[1], false)
+	g := &dependencyGraph{nodes: nodes, byName: map[string]*beanNode{}}
+	g.sort(xlog.GetLogger())
+
+	tests := []struct {
+		name        string
+		skipOnError bool
+		panic       bool
+		expect      string
+	}{
+		{name: "error", skipOnError: true, expect: "a,b,d,e"},
+		{name: "error not skipped", skipOnError: false, expect: "a,b,c,d,e"},
+		{name: "panic", panic: true, expect: "a,b,d,e"},
+	}
+	for _, workers := range []int{1, 4} {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s workers=%d", tt.name, workers), func(t *testing.T) {
+				var lock sync.Mutex
+				done := map[string]bool{}
+				var recovered interface{}
+				func() {
+					defer func() {
+						recovered = recover()
+					}()
+					g.walk(workers, tt.skipOnError, func(n *beanNode) error {
+						lock.Lock()
+						done[n.name] = true
+						lock.Unlock()
+						if n.name != "b" {
+							return nil
+						}
+						if tt.panic {
+							panic("b failed")
+						}
+						return errors.New("b failed")
+					})
+				}()
+				if tt.panic != (recovered != nil) {
+					t.Fatalf("expect panic %v, got %v", tt.panic, recovered)
+				}
+				var got []string
+				for _, name := range names {
+					if done[name] {
+						got = append(got, name)
+					}
+				}
+				if strings.Join(got, ",") != tt.expect {
+					t.Fatalf("expect processed %s, got %v", tt.expect, got)
+				}
+			})
+		}
+	}
+}
+
+func TestSortKeepsRegistrationOrder(t *testing.T) {
+	// c依赖a，d、e相互依赖，b无依赖
+	names := []string{"c", "b", "a", "d", "e"}
+	nodes := make([]*beanNode, len(names))
+	byName := map[string]*beanNode{}
+	for i, name := range names {
+		nodes[i] = &beanNode{name: name, index: i}
+		byName[name] = nodes[i]
+	}
+	byName["c"].addDependency(byName["a"], false)
+	byName["d"].addDependency(byName["e"], false)
+	byName["e"].addDependency(byName["d"], false)
+	g := &dependencyGraph{nodes: nodes, byName: byName}
+	g.sort(xlog.GetLogger())
+
+	got := make([]string, len(g.order))
+	for i, n := range g.order {
+		got[i] = n.name
+	}
+	if expect := "b,a,c,d,e"; strings.Join(got, ",") != expect {
+		t.Fatalf("expect order %s, got %v", expect, got)
+	}
+}
@@ -1,6 +1,9 @@
 package appcontext
 
-import "time"
+import (
+	"github.com/ydx1011/yfig"
+	"time"
+)
 
 type ApplicationEvent interface {
 	// 事件发生的时间
@@ -85,6 +88,17 @@ type ContextClosedEvent struct {
 	ApplicationContextEvent
 }
 
+// 配置更新（ApplicationContext.Refresh）后触发，可刷新的bean已重新填充配置属性
+type ConfigChangedEvent struct {
+	ApplicationContextEvent
+
+	// 发生变化（新增、删除或修改）的属性名称
+	ChangedKeys []string
+
+	// 更新前的配置，更新后的配置通过appcontext.GetProperties(GetContext())获得
+	OldProperties yfig.Properties
+}
+
 type ApplicationEventConsumer interface {
 	// 获得ApplicationEvent消费方法，类型func(ApplicationEvent)
 	// 方法应尽快处理事件，耗时操作请使用协程
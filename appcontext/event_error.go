@@ -0,0 +1,146 @@
+package appcontext
+
+import (
+	"fmt"
+	"github.com/xfali/xlog"
+	"runtime/debug"
+)
+
+// 事件监听器处理事件失败（panic）的错误
+type EventListenerError struct {
+	// 失败的监听器
+	Listener ApplicationEventListener
+
+	// 处理失败的事件
+	Event ApplicationEvent
+
+	// 失败原因，panic的值不是error时转换为error
+	Cause error
+
+	// 已调用监听器的次数（包括重试）
+	Attempts int
+
+	// panic时的调用栈
+	Stack string
+}
+
+func (e *EventListenerError) Error() string {
+	return fmt.Sprintf("Event listener [%T] failed on event [%T]: %v", e.Listener, e.Event, e.Cause)
+}
+
+func (e *EventListenerError) Unwrap() error {
+	return e.Cause
+}
+
+// 监听器处理失败后发布的事件，用于监控监听器的失败。
+// 处理EventListenerErrorEvent的监听器失败时不再发布该事件
+type EventListenerErrorEvent struct {
+	BaseApplicationEvent
+
+	Err *EventListenerError
+}
+
+// 事件监听器失败的处理器
+type ErrorHandler interface {
+	// 处理监听器失败，返回true时再次调用监听器（重试）
+	HandleError(err *EventListenerError) bool
+}
+
+type ErrorHandlerFunc func(err *EventListenerError) bool
+
+func (f ErrorHandlerFunc) HandleError(err *EventListenerError) bool {
+	return f(err)
+}
+
+// 输出错误日志的处理器（默认）
+func LogErrorHandler(logger xlog.Logger) ErrorHandler {
+	return ErrorHandlerFunc(func(err *EventListenerError) bool {
+		logger.Errorf("%s\n%s", err.Error(), err.Stack)
+		return false
+	})
+}
+
+// 重试的处理器：失败后最多重试times次，重试用尽后交给next处理，next为nil时输出错误日志。
+// 重试时重新调用整个监听器，panic之前已执行的操作会再次执行，因此监听器必须是幂等的
+func RetryErrorHandler(times int, next ErrorHandler) ErrorHandler {
+	if next == nil {
+		next = LogErrorHandler(xlog.GetLogger())
+	}
+	return ErrorHandlerFunc(func(err *EventListenerError) bool {
+		if err.Attempts <= times {
+			return true
+		}
+		return next.HandleError(err)
+	})
+}
+
+// 死信处理器：将失败的事件交给deadLetter保存（如写入队列或存储），用于后续排查或重新投递
+func DeadLetterErrorHandler(deadLetter func(err *EventListenerError)) ErrorHandler {
+	return ErrorHandlerFunc(func(err *EventListenerError) bool {
+		deadLetter(err)
+		return false
+	})
+}
+
+// 设置事件监听器失败的处理器
+func OptSetErrorHandler(handler ErrorHandler) EventProcessorOpt {
+	return func(processor *defaultEventProcessor) {
+		processor.errorHandler = handler
+	}
+}
+
+// 设置ApplicationContext内置事件处理器的监听器失败处理器，默认输出错误日志
+func OptSetEventErrorHandler(handler ErrorHandler) Opt {
+	return func(ctx *defaultApplicationContext) {
+		if p, ok := ctx.eventProc.(*defaultEventProcessor); ok {
+			p.errorHandler = handler
+		}
+	}
+}
+
+// 调用监听器，单个监听器的panic不会影响其他监听器及事件处理协程。
+// 失败时交给ErrorHandler处理，重试时从头调用监听器，不再重试后发布EventListenerErrorEvent
+func (h *defaultEventProcessor) invokeListener(l ApplicationEventListener, e ApplicationEvent) {
+	for attempts := 1; ; attempts++ {
+		err := callListener(l, e)
+		if err == nil {
+			return
+		}
+		err.Attempts = attempts
+		if h.errorHandler != nil && h.errorHandler.HandleError(err) {
+			continue
+		}
+		h.publishListenerError(err)
+		return
+	}
+}
+
+func callListener(l ApplicationEventListener, e ApplicationEvent) (ret *EventListenerError) {
+	defer func() {
+		if r := recover(); r != nil {
+			cause, ok := r.(error)
+			if !ok {
+				cause = fmt.Errorf("%v", r)
+			}
+			ret = &EventListenerError{
+				Listener: l,
+				Event:    e,
+				Cause:    cause,
+				Stack:    string(debug.Stack()),
+			}
+		}
+	}()
+	l.OnApplicationEvent(e)
+	return nil
+}
+
+func (h *defaultEventProcessor) publishListenerError(err *EventListenerError) {
+	if _, ok := err.Event.(*EventListenerErrorEvent); ok {
+		return
+	}
+	e := &EventListenerErrorEvent{Err: err}
+	e.ResetOccurredTime()
+	if perr := h.PublishEvent(e); perr != nil {
+		h.logger.Errorln("publish EventListenerErrorEvent failed: ", perr)
+	}
+}
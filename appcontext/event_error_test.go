@@ -0,0 +1,86 @@
+package appcontext
+
+import (
+	"testing"
+	"time"
+)
+
+type panicListener struct {
+	calls int
+	fails int
+}
+
+func (l *panicListener) OnApplicationEvent(e ApplicationEvent) {
+	// 失败后发布的EventListenerErrorEvent在事件处理协程中异步分发，不计数
+	if _, ok := e.(*testEvent); !ok {
+		return
+	}
+	l.calls++
+	if l.calls <= l.fails {
+		panic("listener failed")
+	}
+}
+
+type testEvent struct {
+	BaseApplicationEvent
+}
+
+func TestRetryErrorHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		times      int
+		fails      int
+		deadLetter bool
+		calls      int
+		dead       int
+	}{
+		{name: "recovered by retry", times: 2, fails: 2, deadLetter: true, calls: 3, dead: 0},
+		{name: "retry exhausted", times: 2, fails: 5, deadLetter: true, calls: 3, dead: 1},
+		{name: "retry exhausted with nil next", times: 1, fails: 5, calls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dead := 0
+			var next ErrorHandler
+			if tt.deadLetter {
+				next = DeadLetterErrorHandler(func(err *EventListenerError) {
+					dead++
+					if err.Attempts != tt.times+1 {
+						t.Errorf("expect %d attempts, got %d", tt.times+1, err.Attempts)
+					}
+				})
+			}
+			p := NewEventProcessor(OptSetErrorHandler(RetryErrorHandler(tt.times, next)))
+			if err := p.Start(); err != nil {
+				t.Fatal(err)
+			}
+			defer p.Close()
+			l := &panicListener{fails: tt.fails}
+			errEvents := make(chan *EventListenerErrorEvent, 1)
+			p.AddListeners(l, func(e *EventListenerErrorEvent) {
+				errEvents <- e
+			})
+			_ = p.NotifyEvent(&testEvent{})
+			if l.calls != tt.calls {
+				t.Fatalf("expect %d calls, got %d", tt.calls, l.calls)
+			}
+			if dead != tt.dead {
+				t.Fatalf("expect %d dead letters, got %d", tt.dead, dead)
+			}
+			// 重试恢复时不发布EventListenerErrorEvent，重试用尽后发布
+			select {
+			case e := <-errEvents:
+				if tt.calls == tt.fails+1 {
+					t.Fatalf("expect no EventListenerErrorEvent, got %v", e.Err)
+				}
+				if e.Err.Listener != l || e.Err.Attempts != tt.times+1 {
+					t.Fatalf("expect error of listener after %d attempts, got %+v", tt.times+1, e.Err)
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.calls != tt.fails+1 {
+					t.Fatal("expect EventListenerErrorEvent published")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package appcontext
+
+import (
+	"runtime"
+	"sync"
+)
+
+const (
+	KeyEventWorkers = "gopher.application.event.workers"
+)
+
+// 事件监听器的分发方式
+type DeliveryMode int
+
+const (
+	// 在事件处理协程中同步调用（默认），同步监听器按事件发布顺序依次处理，慢的监听器会阻塞后续所有事件
+	DeliverySync DeliveryMode = iota
+	// 在共享协程池（大小由gopher.application.event.workers配置）中异步调用，同一监听器按事件发布顺序依次处理（FIFO），
+	// 不同监听器之间并行处理
+	DeliveryAsync
+	// 在监听器独占的协程池中异步调用，协程数为1时同一监听器按事件发布顺序依次处理（FIFO），
+	// 大于1时并发处理，不保证顺序
+	DeliveryDedicated
+)
+
+// 声明分发方式的监听器，如注册为bean的ApplicationEventListener
+type DeliveryListener interface {
+	// 监听器的分发方式
+	DeliveryMode() DeliveryMode
+}
+
+type deliveryListener struct {
+	listener interface{}
+	mode     DeliveryMode
+	workers  int
+}
+
+// 在共享协程池中异步调用监听器，listener为AddListeners支持的任意监听器
+func Async(listener interface{}) interface{} {
+	return &deliveryListener{
+		listener: listener,
+		mode:     DeliveryAsync,
+	}
+}
+
+// 在监听器独占的协程池中异步调用监听器，workers小于1时为1
+func Dedicated(listener interface{}, workers int) interface{} {
+	if workers < 1 {
+		workers = 1
+	}
+	return &deliveryListener{
+		listener: listener,
+		mode:     DeliveryDedicated,
+		workers:  workers,
+	}
+}
+
+func (l *deliveryListener) DeliveryMode() DeliveryMode {
+	return l.mode
+}
+
+// 执行事件分发任务的协程池，第一次提交任务时启动协程，任务队列不限长度
+type eventPool struct {
+	workers int
+	tasks   []func()
+	started bool
+	closed  bool
+	lock    sync.Mutex
+	cond    *sync.Cond
+	wg      sync.WaitGroup
+}
+
+func newEventPool(workers int) *eventPool {
+	ret := &eventPool{
+		workers: workers,
+	}
+	ret.cond = sync.NewCond(&ret.lock)
+	return ret
+}
+
+func defaultEventWorkers() int {
+	return runtime.NumCPU()
+}
+
+// 设置协程数，在协程池下一次启动时生效
+func (p *eventPool) setWorkers(workers int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.workers = workers
+}
+
+// 提交任务，协程池已关闭时返回false
+func (p *eventPool) submit(task func()) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return false
+	}
+	if !p.started {
+		p.started = true
+		workers := p.workers
+		if workers < 1 {
+			workers = 1
+		}
+		p.wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go p.run()
+		}
+	}
+	p.tasks = append(p.tasks, task)
+	p.cond.Signal()
+	return true
+}
+
+func (p *eventPool) run() {
+	defer p.wg.Done()
+	for {
+		p.lock.Lock()
+		for len(p.tasks) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if len(p.tasks) == 0 {
+			p.lock.Unlock()
+			return
+		}
+		task := p.tasks[0]
+		p.tasks[0] = nil
+		p.tasks = p.tasks[1:]
+		p.lock.Unlock()
+		task()
+	}
+}
+
+// 执行完已提交的任务后关闭协程池
+func (p *eventPool) close() {
+	p.lock.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.lock.Unlock()
+	p.wg.Wait()
+}
+
+// 重新打开已关闭的协程池
+func (p *eventPool) reopen() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		p.closed = false
+		p.started = false
+	}
+}
+
+// 异步监听器的分发器：串行分发时使用事件信箱保证同一监听器按发布顺序处理事件，
+// 同一时间只有一个任务在协程池中处理该监听器的事件
+type asyncDeliverer struct {
+	listener ApplicationEventListener
+	pool     *eventPool
+	serial   bool
+	// 调用监听器的方法，负责处理监听器的panic
+	invoke func(l ApplicationEventListener, e ApplicationEvent)
+
+	pending []ApplicationEvent
+	running bool
+	lock    sync.Mutex
+}
+
+func (d *asyncDeliverer) deliver(e ApplicationEvent) {
+	if !d.serial {
+		if !d.pool.submit(func() { d.invoke(d.listener, e) }) {
+			d.invoke(d.listener, e)
+		}
+		return
+	}
+
+	d.lock.Lock()
+	d.pending = append(d.pending, e)
+	if d.running {
+		d.lock.Unlock()
+		return
+	}
+	d.running = true
+	d.lock.Unlock()
+
+	// 协程池已关闭（如Close之后NotifyEvent）时在当前协程中处理
+	if !d.pool.submit(d.drain) {
+		d.drain()
+	}
+}
+
+func (d *asyncDeliverer) drain() {
+	for {
+		d.lock.Lock()
+		if len(d.pending) == 0 {
+			d.running = false
+			d.lock.Unlock()
+			return
+		}
+		e := d.pending[0]
+		d.pending[0] = nil
+		d.pending = d.pending[1:]
+		d.lock.Unlock()
+		d.invoke(d.listener, e)
+	}
+}
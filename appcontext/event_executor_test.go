@@ -0,0 +1,157 @@
+package appcontext
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type seqEvent struct {
+	BaseApplicationEvent
+	n int
+}
+
+type recordListener struct {
+	lock     sync.Mutex
+	received []int
+	// 处理该序号的事件时panic
+	panicOn func(n int) bool
+}
+
+func (l *recordListener) OnApplicationEvent(e ApplicationEvent) {
+	se, ok := e.(*seqEvent)
+	if !ok {
+		return
+	}
+	// 打乱处理耗时，检查顺序是否由分发保证
+	if se.n%7 == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	l.lock.Lock()
+	l.received = append(l.received, se.n)
+	l.lock.Unlock()
+	if l.panicOn != nil && l.panicOn(se.n) {
+		panic("listener failed")
+	}
+}
+
+func (l *recordListener) get() []int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]int(nil), l.received...)
+}
+
+func newStartedEventProcessor(t *testing.T, opts ...EventProcessorOpt) *defaultEventProcessor {
+	p := NewEventProcessor(append([]EventProcessorOpt{OptSetErrorHandler(ErrorHandlerFunc(func(err *EventListenerError) bool {
+		return false
+	}))}, opts...)...)
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func expectSequence(t *testing.T, got []int, size int) {
+	if len(got) != size {
+		t.Fatalf("expect %d events, got %d", size, len(got))
+	}
+	for i, n := range got {
+		if n != i {
+			t.Fatalf("expect FIFO order, got %d at %d", n, i)
+		}
+	}
+}
+
+func TestAsyncListenerFIFO(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(l interface{}) interface{}
+	}{
+		{name: "async", wrap: Async},
+		{name: "dedicated", wrap: func(l interface{}) interface{} { return Dedicated(l, 1) }},
+	}
+	const size = 200
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newStartedEventProcessor(t, OptSetEventWorkers(4))
+			listeners := []*recordListener{{}, {}, {}}
+			for _, l := range listeners {
+				p.AddListeners(tt.wrap(l))
+			}
+			for i := 0; i < size; i++ {
+				_ = p.NotifyEvent(&seqEvent{n: i})
+			}
+			// Close等待异步监听器处理完已分发的事件
+			_ = p.Close()
+			for _, l := range listeners {
+				expectSequence(t, l.get(), size)
+			}
+		})
+	}
+}
+
+func TestAsyncListenerPanicIsolation(t *testing.T) {
+	const size = 50
+	var lock sync.Mutex
+	var failed []int
+	p := newStartedEventProcessor(t, OptSetErrorHandler(ErrorHandlerFunc(func(err *EventListenerError) bool {
+		lock.Lock()
+		defer lock.Unlock()
+		failed = append(failed, err.Event.(*seqEvent).n)
+		return false
+	})))
+	bad := &recordListener{panicOn: func(n int) bool { return n%2 == 0 }}
+	asyncGood := &recordListener{}
+	syncGood := &recordListener{}
+	p.AddListeners(Async(bad), Async(asyncGood), syncGood)
+	for i := 0; i < size; i++ {
+		_ = p.NotifyEvent(&seqEvent{n: i})
+	}
+	_ = p.Close()
+
+	// 失败的监听器继续处理后续事件，其他监听器不受影响
+	for _, l := range []*recordListener{bad, asyncGood, syncGood} {
+		expectSequence(t, l.get(), size)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if len(failed) != size/2 {
+		t.Fatalf("expect %d failures, got %d", size/2, len(failed))
+	}
+}
+
+func TestOptSetEventWorkers(t *testing.T) {
+	p := NewEventProcessor(OptSetEventWorkers(3))
+	if p.pool.workers != 3 {
+		t.Fatalf("expect 3 workers, got %d", p.pool.workers)
+	}
+}
+
+func TestDedicatedListenerAddedAfterClose(t *testing.T) {
+	p := newStartedEventProcessor(t)
+	_ = p.Close()
+
+	l := &recordListener{}
+	p.AddListeners(Dedicated(l, 1))
+	// 关闭后在当前协程中处理
+	_ = p.NotifyEvent(&seqEvent{n: 0})
+	expectSequence(t, l.get(), 1)
+
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+	_ = p.NotifyEvent(&seqEvent{n: 1})
+	_ = p.Close()
+	expectSequence(t, l.get(), 2)
+
+	p.listenerLock.Lock()
+	defer p.listenerLock.Unlock()
+	for _, pool := range p.dedicated {
+		pool.lock.Lock()
+		closed, started := pool.closed, pool.started
+		pool.lock.Unlock()
+		if !closed || !started {
+			t.Fatalf("expect dedicated pool started and closed, got started=%v closed=%v", started, closed)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package appcontext
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ydx1011/gopher-core/bean"
+	errors2 "github.com/ydx1011/gopher-core/errors"
+	"github.com/ydx1011/gopher-core/injector"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 启动失败的分析结果
+type FailureAnalysis struct {
+	// 失败的描述
+	Description string
+
+	// 建议的处理方式
+	Action string
+
+	// 被分析的错误
+	Cause error
+}
+
+func (a *FailureAnalysis) String() string {
+	return fmt.Sprintf("Description:\n\n%s\n\nAction:\n\n%s\n", a.Description, a.Action)
+}
+
+// 启动失败分析器，将常见的启动错误转换为可读的描述及建议的处理方式
+type FailureAnalyzer interface {
+	// 分析错误，无法识别时返回nil
+	Analyze(container bean.Container, err error) *FailureAnalysis
+}
+
+type FailureAnalyzerFunc func(container bean.Container, err error) *FailureAnalysis
+
+func (f FailureAnalyzerFunc) Analyze(container bean.Container, err error) *FailureAnalysis {
+	return f(container, err)
+}
+
+// 提供启动失败分析的ApplicationContext
+type FailureReporter interface {
+	// 分析错误，errors.Errors中的每个错误单独分析，只返回能够识别的错误的分析结果
+	AnalyzeFailure(err error) []*FailureAnalysis
+}
+
+// 添加启动失败分析器，先于内置的分析器执行
+func OptAddFailureAnalyzers(analyzers ...FailureAnalyzer) Opt {
+	return func(ctx *defaultApplicationContext) {
+		ctx.failureAnalyzers = append(ctx.failureAnalyzers, analyzers...)
+	}
+}
+
+var defaultFailureAnalyzers = []FailureAnalyzer{
+	FailureAnalyzerFunc(analyzeInjectFailure),
+}
+
+func (ctx *defaultApplicationContext) AnalyzeFailure(err error) []*FailureAnalysis {
+	var ret []*FailureAnalysis
+	if es, ok := err.(errors2.Errors); ok {
+		for _, e := range es {
+			ret = append(ret, ctx.AnalyzeFailure(e)...)
+		}
+		return ret
+	}
+	if err == nil {
+		return nil
+	}
+	analyzers := append(append([]FailureAnalyzer{}, ctx.failureAnalyzers...), defaultFailureAnalyzers...)
+	for _, a := range analyzers {
+		if ret := a.Analyze(ctx.container, err); ret != nil {
+			if ret.Cause == nil {
+				ret.Cause = err
+			}
+			return []*FailureAnalysis{ret}
+		}
+	}
+	return nil
+}
+
+// 输出启动失败的分析结果
+func (ctx *defaultApplicationContext) logFailureAnalysis(err error) {
+	for _, a := range ctx.AnalyzeFailure(err) {
+		ctx.logger.Errorf("\n\n***************************\nAPPLICATION FAILED TO START\n***************************\n\n%s\n", a)
+	}
+}
+
+// 分析注入失败（injector.InjectError）的错误
+func analyzeInjectFailure(c bean.Container, err error) *FailureAnalysis {
+	var ie *injector.InjectError
+	if !errors.As(err, &ie) {
+		return nil
+	}
+	target := describeInjectTarget(err)
+	switch ie.Reason {
+	case injector.ReasonNoCandidate:
+		return analyzeNoCandidate(c, target, ie)
+	case injector.ReasonMultipleCandidates:
+		return analyzeMultipleCandidates(c, target, ie)
+	case injector.ReasonNamedBeanMissing:
+		return &FailureAnalysis{
+			Description: fmt.Sprintf("%s required a bean named '%s' that could not be found.", target, ie.Name),
+			Action:      suggestNames(c, ie.Name, fmt.Sprintf("Consider registering a bean named '%s' with RegisterBeanByName, or correct the name in the inject tag.", ie.Name)),
+		}
+	case injector.ReasonCannotSet:
+		var fe *injector.FieldInjectError
+		if errors.As(err, &fe) && fe.Field != "" && !unicode.IsUpper([]rune(fe.Field)[0]) {
+			return &FailureAnalysis{
+				Description: fmt.Sprintf("%s is unexported and cannot be injected.", target),
+				Action:      fmt.Sprintf("Export the field (rename it to '%s') or remove the inject tag.", exportedName(fe.Field)),
+			}
+		}
+		return &FailureAnalysis{
+			Description: fmt.Sprintf("%s cannot be set.", target),
+			Action:      "Make sure the injected value is exported and addressable.",
+		}
+	case injector.ReasonNotPointer:
+		return &FailureAnalysis{
+			Description: fmt.Sprintf("%s is declared as struct '%s', but struct can only be injected by pointer.", target, typeName(ie.Type)),
+			Action:      fmt.Sprintf("Change the type to '%s'.", typeName(reflect.PtrTo(ie.Type))),
+		}
+	case injector.ReasonTypeMismatch:
+		return &FailureAnalysis{
+			Description: fmt.Sprintf("%s required a bean of type '%s', but bean '%s' is of type '%s'.", target, typeName(ie.Type), ie.Name, typeName(ie.Actual)),
+			Action: fmt.Sprintf("The bean may have been replaced by a BeanPostProcessor%s. Declare the injected type as an interface implemented by '%s', or make the processor return a compatible value.",
+				missingMethods(ie.Actual, ie.Type), typeName(ie.Actual)),
+		}
+	}
+	return nil
+}
+
+func analyzeNoCandidate(c bean.Container, target string, ie *injector.InjectError) *FailureAnalysis {
+	want := ie.Type
+	if want.Kind() == reflect.Slice || want.Kind() == reflect.Map {
+		want = want.Elem()
+	}
+	if qualifier, ok := injector.ParseQualifier(ie.Name); ok {
+		return &FailureAnalysis{
+			Description: fmt.Sprintf("%s required a bean of type '%s' with qualifier '%s' that could not be found.", target, typeName(want), qualifier),
+			Action: fmt.Sprintf("Consider adding qualifier to a bean of type '%s' with bean.SetQualifiers(\"%s\"), or correct the qualifier in the inject tag.",
+				typeName(want), qualifier),
+		}
+	}
+	ret := &FailureAnalysis{
+		Description: fmt.Sprintf("%s required a bean of type '%s' that could not be found.", target, typeName(want)),
+	}
+	if want.Kind() == reflect.Struct {
+		if _, ok := c.GetDefinition(reflection.GetTypeName(reflect.PtrTo(want))); ok {
+			ret.Action = fmt.Sprintf("A bean of type '%s' exists, change the type to '%s', struct can only be injected by pointer.",
+				typeName(reflect.PtrTo(want)), typeName(reflect.PtrTo(want)))
+			return ret
+		}
+	}
+
+	var named, byValue []string
+	bean.ScanHierarchy(c, func(key string, value bean.Definition) bool {
+		t := value.Type()
+		if t.AssignableTo(want) {
+			if key != value.Name() {
+				named = append(named, key)
+			}
+		} else if want.Kind() == reflect.Interface && t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(want) {
+			byValue = append(byValue, key)
+		}
+		return true
+	})
+	if len(named) > 0 {
+		ret.Action = fmt.Sprintf("Found bean(s) registered by name: %s. Consider injecting one by name like `inject:\"%s\"`, or register it with bean.SetPrimary().",
+			quoteNames(named), named[0])
+		return ret
+	}
+	if len(byValue) > 0 {
+		ret.Action = fmt.Sprintf("Bean(s) %s implement '%s' only by pointer receiver, consider registering them by pointer.",
+			quoteNames(byValue), typeName(want))
+		return ret
+	}
+	if similar := similarNames(c, shortTypeName(reflection.GetTypeName(want))); len(similar) > 0 {
+		ret.Action = fmt.Sprintf("Did you mean bean %s?", strings.Join(quote(similar), " or "))
+		if d, ok := c.GetDefinition(similar[0]); ok {
+			ret.Action = fmt.Sprintf("%s Its type '%s' is not assignable to '%s'%s.", ret.Action, typeName(d.Type()), typeName(want), missingMethods(d.Type(), want))
+		}
+		return ret
+	}
+	ret.Action = fmt.Sprintf("Consider registering a bean of type '%s' in the application context.", typeName(want))
+	return ret
+}
+
+func analyzeMultipleCandidates(c bean.Container, target string, ie *injector.InjectError) *FailureAnalysis {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("%s required a single bean of type '%s', but %d were found:", target, typeName(ie.Type), len(ie.Candidates)))
+	for _, name := range ie.Candidates {
+		buf.WriteString(fmt.Sprintf("\n    - '%s'", name))
+		if d, ok := c.GetDefinition(name); ok && typeName(d.Type()) != name {
+			buf.WriteString(fmt.Sprintf(": %s", typeName(d.Type())))
+		}
+	}
+	return &FailureAnalysis{
+		Description: buf.String(),
+		Action: fmt.Sprintf("Consider marking one of the beans with bean.SetPrimary(), distinguishing them with bean.SetQualifiers and injecting with `inject:\"@qualifier\"`, or injecting by name like `inject:\"%s\"`.",
+			ie.Candidates[0]),
+	}
+}
+
+// 描述注入失败的位置
+func describeInjectTarget(err error) string {
+	var fe *injector.FieldInjectError
+	if errors.As(err, &fe) {
+		return fmt.Sprintf("Field '%s' of '%s'", fe.Field, typeName(fe.Owner))
+	}
+	var be *BeanError
+	if errors.As(err, &be) {
+		return fmt.Sprintf("Bean '%s'", be.Name)
+	}
+	return "Injection point"
+}
+
+// 存在与name相似的bean名称时建议使用该名称，否则返回defaultAction
+func suggestNames(c bean.Container, name string, defaultAction string) string {
+	similar := similarNames(c, name)
+	if len(similar) == 0 {
+		return defaultAction
+	}
+	return fmt.Sprintf("Did you mean bean %s?", strings.Join(quote(similar), " or "))
+}
+
+// 在容器（包括父容器）的bean名称中查找与name相似的名称，按相似程度排序，最多返回3个
+func similarNames(c bean.Container, name string) []string {
+	type match struct {
+		key  string
+		dist int
+	}
+	target := strings.ToLower(name)
+	var matches []match
+	bean.ScanHierarchy(c, func(key string, value bean.Definition) bool {
+		if key == name {
+			return true
+		}
+		short := strings.ToLower(shortTypeName(key))
+		dist := levenshtein(target, short)
+		if full := levenshtein(target, strings.ToLower(key)); full < dist {
+			dist = full
+		}
+		if dist <= maxDistance(target) || (len(short) > 2 && strings.Contains(target, short)) || strings.Contains(short, target) {
+			matches = append(matches, match{key: key, dist: dist})
+		}
+		return true
+	})
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].dist != matches[j].dist {
+			return matches[i].dist < matches[j].dist
+		}
+		return matches[i].key < matches[j].key
+	})
+	var ret []string
+	for i := 0; i < len(matches) && i < 3; i++ {
+		ret = append(ret, matches[i].key)
+	}
+	return ret
+}
+
+func maxDistance(s string) int {
+	if d := len(s) / 3; d > 2 {
+		return d
+	}
+	return 2
+}
+
+// 编辑距离
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// 去掉类型名称中的指针及包路径，如*github.com.x.UserRepo返回UserRepo
+func shortTypeName(name string) string {
+	name = strings.TrimLeft(name, "*")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// t为接口时列出ot缺少的方法
+func missingMethods(ot reflect.Type, t reflect.Type) string {
+	if ot == nil || t == nil || t.Kind() != reflect.Interface {
+		return ""
+	}
+	var missing []string
+	for i := 0; i < t.NumMethod(); i++ {
+		if _, ok := ot.MethodByName(t.Method(i).Name); !ok {
+			missing = append(missing, t.Method(i).Name)
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (missing method %s)", strings.Join(missing, ", "))
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	return reflection.GetTypeName(t)
+}
+
+func exportedName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func quote(names []string) []string {
+	ret := make([]string, len(names))
+	for i, n := range names {
+		ret[i] = "'" + n + "'"
+	}
+	return ret
+}
+
+func quoteNames(names []string) string {
+	return strings.Join(quote(names), ", ")
+}
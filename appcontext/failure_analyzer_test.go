@@ -0,0 +1,168 @@
+package appcontext
+
+import (
+	"errors"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/injector"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type failureRepo interface {
+	Find() string
+}
+
+type failureRepoImpl struct{}
+
+func (r *failureRepoImpl) Find() string { return "" }
+
+type failureOther struct{}
+
+type failureService struct {
+	Repo failureRepo `inject:"userRepositry"`
+}
+
+func TestAnalyzeInjectFailure(t *testing.T) {
+	repoType := reflect.TypeOf((*failureRepo)(nil)).Elem()
+	implType := reflect.TypeOf(failureRepoImpl{})
+	fieldErr := func(field string, err *injector.InjectError) error {
+		return &injector.FieldInjectError{
+			Owner: reflect.TypeOf(failureService{}),
+			Field: field,
+			Err:   err,
+		}
+	}
+	tests := []struct {
+		name     string
+		register func(c bean.Container)
+		err      error
+		desc     string
+		action   string
+	}{
+		{
+			name:   "no candidate",
+			err:    fieldErr("Repo", &injector.InjectError{Reason: injector.ReasonNoCandidate, Type: repoType}),
+			desc:   "Field 'Repo' of '",
+			action: "Consider registering a bean of type",
+		},
+		{
+			name:   "no candidate with qualifier",
+			err:    fieldErr("Repo", &injector.InjectError{Reason: injector.ReasonNoCandidate, Type: repoType, Name: injector.QualifierPrefix + "main"}),
+			desc:   "with qualifier 'main'",
+			action: `bean.SetQualifiers("main")`,
+		},
+		{
+			name: "no candidate struct by value",
+			register: func(c bean.Container) {
+				_ = c.Register(&failureRepoImpl{})
+			},
+			err:    fieldErr("Repo", &injector.InjectError{Reason: injector.ReasonNoCandidate, Type: implType}),
+			desc:   "could not be found",
+			action: "struct can only be injected by pointer",
+		},
+		{
+			name: "no candidate registered by name",
+			register: func(c bean.Container) {
+				_ = c.RegisterByName("mainRepo", &failureRepoImpl{})
+			},
+			err:    fieldErr("Repo", &injector.InjectError{Reason: injector.ReasonNoCandidate, Type: repoType}),
+			action: "Found bean(s) registered by name: 'mainRepo'",
+		},
+		{
+			name: "multiple candidates",
+			register: func(c bean.Container) {
+				_ = c.RegisterByName("repoA", &failureRepoImpl{})
+				_ = c.RegisterByName("repoB", &failureRepoImpl{})
+			},
+			err:    fieldErr("Repo", &injector.InjectError{Reason: injector.ReasonMultipleCandidates, Type: repoType, Candidates: []string{"repoA", "repoB"}}),
+			desc:   "but 2 were found:\n    - 'repoA'",
+			action: "bean.SetPrimary()",
+		},
+		{
+			name:   "named bean missing",
+			err:    fieldErr("Repo", &injector.InjectError{Reason: injector.ReasonNamedBeanMissing, Type: repoType, Name: "userRepository"}),
+			desc:   "required a bean named 'userRepository'",
+			action: "Consider registering a bean named 'userRepository'",
+		},
+		{
+			name: "named bean misspelled",
+			register: func(c bean.Container) {
+				_ = c.RegisterByName("userRepository", &failureRepoImpl{})
+			},
+			err:    fieldErr("Repo", &injector.InjectError{Reason: injector.ReasonNamedBeanMissing, Type: repoType, Name: "userRepositry"}),
+			action: "Did you mean bean 'userRepository'?",
+		},
+		{
+			name:   "cannot set unexported",
+			err:    fieldErr("repo", &injector.InjectError{Reason: injector.ReasonCannotSet, Type: repoType}),
+			desc:   "Field 'repo' of '",
+			action: "rename it to 'Repo'",
+		},
+		{
+			name:   "cannot set",
+			err:    &injector.InjectError{Reason: injector.ReasonCannotSet, Type: repoType},
+			desc:   "Injection point cannot be set.",
+			action: "exported and addressable",
+		},
+		{
+			name:   "not pointer",
+			err:    fieldErr("Repo", &injector.InjectError{Reason: injector.ReasonNotPointer, Type: implType}),
+			desc:   "is declared as struct",
+			action: "Change the type to '*",
+		},
+		{
+			name:   "type mismatch",
+			err:    fieldErr("Repo", &injector.InjectError{Reason: injector.ReasonTypeMismatch, Type: repoType, Name: "repo", Actual: reflect.TypeOf(&failureOther{})}),
+			desc:   "but bean 'repo' is of type",
+			action: "(missing method Find)",
+		},
+		{
+			name: "not inject error",
+			err:  errors.New("other"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := bean.NewContainer()
+			if tt.register != nil {
+				tt.register(c)
+			}
+			a := analyzeInjectFailure(c, tt.err)
+			if tt.desc == "" && tt.action == "" {
+				if a != nil {
+					t.Fatalf("expect no analysis, got %s", a)
+				}
+				return
+			}
+			if a == nil {
+				t.Fatal("expect analysis")
+			}
+			if !strings.Contains(a.Description, tt.desc) {
+				t.Fatalf("expect description contains %q, got %q", tt.desc, a.Description)
+			}
+			if !strings.Contains(a.Action, tt.action) {
+				t.Fatalf("expect action contains %q, got %q", tt.action, a.Action)
+			}
+		})
+	}
+}
+
+// 注入名称拼写错误时启动失败的分析结果给出相似的bean名称
+func TestAnalyzeFailureDidYouMean(t *testing.T) {
+	ctx := newTestContext(t, map[string]interface{}{"failFast": "true"})
+	defer ctx.Close()
+	_ = ctx.RegisterBeanByName("userRepository", &failureRepoImpl{})
+	_ = ctx.RegisterBean(&failureService{})
+	err := ctx.Start()
+	if err == nil {
+		t.Fatal("expect inject error")
+	}
+	analysis := ctx.AnalyzeFailure(err)
+	if len(analysis) != 1 {
+		t.Fatalf("expect one analysis, got %v", analysis)
+	}
+	if !strings.Contains(analysis[0].Action, "Did you mean bean 'userRepository'?") {
+		t.Fatalf("expect name suggestion, got %s", analysis[0])
+	}
+}
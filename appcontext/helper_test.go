@@ -0,0 +1,28 @@
+package appcontext
+
+import (
+	"github.com/ydx1011/yfig"
+	"testing"
+)
+
+// 创建并初始化测试用的ApplicationContext，props为gopher.application下的配置
+func newTestContext(t *testing.T, props map[string]interface{}, opts ...Opt) *defaultApplicationContext {
+	ctx := NewDefaultApplicationContext(opts...)
+	app := map[string]interface{}{
+		"bannerMode": "off",
+	}
+	for k, v := range props {
+		app[k] = v
+	}
+	v := yfig.Value{
+		"gopher": map[string]interface{}{
+			"application": app,
+		},
+	}
+	prop := yfig.New()
+	prop.Value = &v
+	if err := ctx.Init(prop); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
@@ -0,0 +1,87 @@
+package appcontext
+
+import (
+	"github.com/ydx1011/gopher-core/bean"
+	errors2 "github.com/ydx1011/gopher-core/errors"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+)
+
+// 具有父context的ApplicationContext
+type HierarchicalContext interface {
+	// 获得父context，没有时返回nil
+	GetParent() ApplicationContext
+}
+
+// 设置父context，用于在同一进程中运行多个共享基础bean（如数据库连接池）的子应用：
+// 子context中获取及注入的对象不存在时从父context的容器中查找，子context中同名的对象覆盖父context中的对象；
+// 关闭子context只销毁子context中注册的bean，不影响父context。
+// 注意：该Opt会替换context的容器，需要在其他设置容器的Opt之前使用；父context需要实现ContainerProvider，否则不会从父context查找对象
+func OptSetParent(parent ApplicationContext) Opt {
+	return func(ctx *defaultApplicationContext) {
+		ctx.parent = parent
+		if c := GetContainer(parent); c != nil {
+			ctx.container = bean.NewContainer(bean.OptSetParent(c))
+		}
+	}
+}
+
+// 子context发布的事件（包括通过注入的ApplicationEventPublisher发布的事件）同时发布到父context，需要与OptSetParent一起使用
+func OptPropagateEvents() Opt {
+	return func(ctx *defaultApplicationContext) {
+		ctx.propagateEvents = true
+	}
+}
+
+func (ctx *defaultApplicationContext) GetParent() ApplicationContext {
+	return ctx.parent
+}
+
+// 向上传递事件的ApplicationEventPublisher，以interface名称注册，优先于事件处理器被注入
+type propagatingPublisher struct {
+	ctx *defaultApplicationContext
+}
+
+func (p *propagatingPublisher) PublishEvent(e ApplicationEvent) error {
+	return p.ctx.PublishEvent(e)
+}
+
+func (ctx *defaultApplicationContext) registerPublisher() error {
+	if !ctx.propagateEvents || ctx.parent == nil {
+		return nil
+	}
+	t := reflect.TypeOf((*ApplicationEventPublisher)(nil)).Elem()
+	return ctx.container.RegisterByName(reflection.GetTypeName(t), &propagatingPublisher{ctx: ctx})
+}
+
+func (ctx *defaultApplicationContext) PublishEvent(e ApplicationEvent) error {
+	err := ctx.eventProc.PublishEvent(e)
+	if !ctx.propagateEvents || ctx.parent == nil {
+		return err
+	}
+	perr := ctx.parent.PublishEvent(e)
+	if err == nil {
+		return perr
+	}
+	if perr == nil {
+		return err
+	}
+	return errors2.Errors{err, perr}
+}
+
+// 父context中的对象定义，子context可能以其他名称（如interface名称）缓存这些对象定义，关闭时不能销毁
+func (ctx *defaultApplicationContext) parentDefinitions() map[bean.Definition]bool {
+	ret := map[bean.Definition]bool{}
+	if ctx.parent == nil {
+		return ret
+	}
+	c := GetContainer(ctx.parent)
+	if c == nil {
+		return ret
+	}
+	bean.ScanHierarchy(c, func(key string, value bean.Definition) bool {
+		ret[value] = true
+		return true
+	})
+	return ret
+}
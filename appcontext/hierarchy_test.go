@@ -0,0 +1,140 @@
+package appcontext
+
+import (
+	"testing"
+	"time"
+)
+
+type hierRepo struct {
+	destroyed bool
+}
+
+func (r *hierRepo) BeanDestroy() error {
+	r.destroyed = true
+	return nil
+}
+
+type hierConfig struct {
+	from string
+}
+
+type hierService struct {
+	Repo      *hierRepo                 `inject:""`
+	Config    *hierConfig               `inject:""`
+	Publisher ApplicationEventPublisher `inject:""`
+
+	destroyed bool
+}
+
+func (s *hierService) BeanDestroy() error {
+	s.destroyed = true
+	return nil
+}
+
+type hierEvent struct {
+	BaseApplicationEvent
+}
+
+func newParentContext(t *testing.T, repo *hierRepo) *defaultApplicationContext {
+	parent := newTestContext(t, nil)
+	_ = parent.RegisterBean(repo)
+	_ = parent.RegisterBean(&hierConfig{from: "parent"})
+	if err := parent.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return parent
+}
+
+func TestHierarchyLookup(t *testing.T) {
+	repo := &hierRepo{}
+	parent := newParentContext(t, repo)
+	defer parent.Close()
+	child := newTestContext(t, nil, OptSetParent(parent))
+	service := &hierService{}
+	_ = child.RegisterBean(service)
+	// 子context中同类型的对象覆盖父context中的对象
+	_ = child.RegisterBean(&hierConfig{from: "child"})
+	if err := child.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if service.Repo != repo {
+		t.Fatal("expect repo injected from parent")
+	}
+	if service.Config == nil || service.Config.from != "child" {
+		t.Fatalf("expect child config injected, got %v", service.Config)
+	}
+	var got *hierRepo
+	if !child.GetBeanByType(&got) || got != repo {
+		t.Fatal("expect parent bean found from child")
+	}
+	if child.GetParent() != parent {
+		t.Fatal("expect parent context")
+	}
+
+	// 关闭子context只销毁子context中的对象
+	_ = child.Close()
+	if !service.destroyed {
+		t.Fatal("expect child bean destroyed")
+	}
+	if repo.destroyed {
+		t.Fatal("expect parent bean intact after child closed")
+	}
+	got = nil
+	if !parent.GetBeanByType(&got) || got != repo {
+		t.Fatal("expect parent bean still available")
+	}
+}
+
+func TestHierarchyEventPropagation(t *testing.T) {
+	tests := []struct {
+		name      string
+		propagate bool
+	}{
+		{name: "propagate", propagate: true},
+		{name: "not propagate", propagate: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := newParentContext(t, &hierRepo{})
+			defer parent.Close()
+			received := make(chan *hierEvent, 2)
+			parent.AddListeners(func(e *hierEvent) {
+				received <- e
+			})
+
+			opts := []Opt{OptSetParent(parent)}
+			if tt.propagate {
+				opts = append(opts, OptPropagateEvents())
+			}
+			child := newTestContext(t, nil, opts...)
+			defer child.Close()
+			service := &hierService{}
+			_ = child.RegisterBean(service)
+			if err := child.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			// 直接发布及通过注入的ApplicationEventPublisher发布
+			_ = child.PublishEvent(&hierEvent{})
+			_ = service.Publisher.PublishEvent(&hierEvent{})
+			count := 0
+			timeout := time.After(200 * time.Millisecond)
+			for count < 2 {
+				select {
+				case <-received:
+					count++
+					continue
+				case <-timeout:
+				}
+				break
+			}
+			if tt.propagate && count != 2 {
+				t.Fatalf("expect parent received 2 events, got %d", count)
+			}
+			if !tt.propagate && count != 0 {
+				t.Fatalf("expect parent received no event, got %d", count)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package appcontext
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/reflection"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 启动完成后导出bean依赖图的文件路径，扩展名为.json时导出JSON，否则导出Graphviz DOT
+const KeyBeansGraph = "gopher.application.beansGraph"
+
+// bean的描述信息
+type BeanDescriptor struct {
+	// bean名称
+	Name string `json:"name"`
+
+	// 注入过程中缓存的其他名称（如按interface注入时以interface名称缓存）
+	Aliases []string `json:"aliases,omitempty"`
+
+	// bean类型名称
+	Type string `json:"type"`
+
+	// 作用域名称
+	Scope string `json:"scope"`
+
+	// 注入顺序
+	Order int `json:"order"`
+
+	// 对象定义的来源类型，见bean.DefinitionKind
+	Kind string `json:"kind"`
+
+	Primary    bool     `json:"primary,omitempty"`
+	Lazy       bool     `json:"lazy,omitempty"`
+	Qualifiers []string `json:"qualifiers,omitempty"`
+
+	// 依赖的bean名称
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	// 依赖该bean的bean名称
+	Dependents []string `json:"dependents,omitempty"`
+
+	// 是否已初始化
+	Initialized bool `json:"initialized"`
+
+	// 初始化（注入、分类、方法注入及BeanAfterSet）耗时，未初始化时为0，JSON中单位为纳秒
+	InitDuration time.Duration `json:"initDuration"`
+}
+
+// 提供bean描述信息的ApplicationContext
+type BeanIntrospector interface {
+	// 获得所有bean的描述信息，按初始化顺序排列，在Start之后可用
+	DescribeBeans() []BeanDescriptor
+
+	// 获得名称（或别名）为name的bean的描述信息
+	DescribeBean(name string) (BeanDescriptor, bool)
+}
+
+func (ctx *defaultApplicationContext) DescribeBeans() []BeanDescriptor {
+	if ctx.graph == nil {
+		return nil
+	}
+	aliases := ctx.beanAliases()
+	ret := make([]BeanDescriptor, 0, len(ctx.graph.order))
+	for _, n := range ctx.graph.order {
+		ret = append(ret, ctx.describe(n, aliases[n]))
+	}
+	return ret
+}
+
+func (ctx *defaultApplicationContext) DescribeBean(name string) (BeanDescriptor, bool) {
+	if ctx.graph == nil {
+		return BeanDescriptor{}, false
+	}
+	aliases := ctx.beanAliases()
+	n, ok := ctx.graph.byName[name]
+	if !ok {
+		for node, names := range aliases {
+			for _, alias := range names {
+				if alias == name {
+					return ctx.describe(node, names), true
+				}
+			}
+		}
+		return BeanDescriptor{}, false
+	}
+	return ctx.describe(n, aliases[n]), true
+}
+
+// 容器中以其他名称缓存的对象定义（包括启动后注入过程中缓存的）
+func (ctx *defaultApplicationContext) beanAliases() map[*beanNode][]string {
+	nodes := make(map[bean.Definition]*beanNode, len(ctx.graph.nodes))
+	for _, n := range ctx.graph.nodes {
+		nodes[n.def] = n
+	}
+	ret := map[*beanNode][]string{}
+	ctx.container.Scan(func(key string, value bean.Definition) bool {
+		if n, ok := nodes[value]; ok && key != n.name {
+			ret[n] = append(ret[n], key)
+		}
+		return true
+	})
+	return ret
+}
+
+func (ctx *defaultApplicationContext) describe(n *beanNode, aliases []string) BeanDescriptor {
+	info, _ := bean.GetRegisterInfo(ctx.container, n.name)
+	sort.Strings(aliases)
+	ret := BeanDescriptor{
+		Name:       n.name,
+		Aliases:    aliases,
+		Type:       reflection.GetTypeName(n.def.Type()),
+		Scope:      info.Scope,
+		Order:      info.Order,
+		Kind:       bean.DefinitionKind(n.def),
+		Primary:    info.Primary,
+		Lazy:       info.Lazy,
+		Qualifiers: info.Qualifiers,
+	}
+	for _, d := range n.deps {
+		ret.Dependencies = append(ret.Dependencies, d.node.name)
+	}
+	for _, d := range n.dependents {
+		ret.Dependents = append(ret.Dependents, d.name)
+	}
+	ctx.initializedLock.Lock()
+	ret.Initialized = ctx.initialized[n]
+	ctx.initializedLock.Unlock()
+	ret.InitDuration = ctx.initDuration(n.name)
+	return ret
+}
+
+// 将bean描述信息以JSON格式输出
+func WriteBeansJSON(w io.Writer, beans []BeanDescriptor) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(beans)
+}
+
+// 将bean依赖关系以Graphviz DOT格式输出，边由依赖方指向被依赖的bean，延迟初始化的bean使用虚线表示
+func WriteBeansDOT(w io.Writer, beans []BeanDescriptor) error {
+	buf := strings.Builder{}
+	buf.WriteString("digraph beans {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	buf.WriteString("  node [shape=box];\n")
+	for _, b := range beans {
+		style := ""
+		if b.Lazy {
+			style = ", style=dashed"
+		}
+		label := b.Name
+		if b.Type != b.Name {
+			label += "\n" + b.Type
+		}
+		label += fmt.Sprintf("\n%s %s", b.Kind, b.Scope)
+		buf.WriteString(fmt.Sprintf("  %s [label=%s%s];\n", dotQuote(b.Name), dotQuote(label), style))
+	}
+	for _, b := range beans {
+		for _, d := range b.Dependencies {
+			buf.WriteString(fmt.Sprintf("  %s -> %s;\n", dotQuote(b.Name), dotQuote(d)))
+		}
+	}
+	buf.WriteString("}\n")
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// 启动完成后按gopher.application.beansGraph导出bean依赖图
+func (ctx *defaultApplicationContext) exportBeans() {
+	path := ctx.GetProperties().Get(KeyBeansGraph, "")
+	if path == "" {
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		ctx.logger.Errorln("export beans failed: ", err)
+		return
+	}
+	defer f.Close()
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = WriteBeansJSON(f, ctx.DescribeBeans())
+	} else {
+		err = WriteBeansDOT(f, ctx.DescribeBeans())
+	}
+	if err != nil {
+		ctx.logger.Errorln("export beans failed: ", err)
+		return
+	}
+	ctx.logger.Infof("Beans exported to %s\n", path)
+}
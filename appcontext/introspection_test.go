@@ -0,0 +1,142 @@
+package appcontext
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/reflection"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type introRepo interface {
+	Load() string
+}
+
+type introRepoImpl struct{}
+
+func (r *introRepoImpl) Load() string { return "" }
+
+type introService struct {
+	Repo introRepo `inject:""`
+}
+
+type introReport struct{}
+
+func startIntrospectionContext(t *testing.T, props map[string]interface{}) *defaultApplicationContext {
+	ctx := newTestContext(t, props)
+	_ = ctx.RegisterBean(&introRepoImpl{}, bean.SetPrimary(), bean.SetQualifiers("db"))
+	_ = ctx.RegisterBean(&introService{})
+	_ = ctx.RegisterBean(&introReport{}, bean.SetLazy())
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func TestDescribeBeans(t *testing.T) {
+	ctx := startIntrospectionContext(t, nil)
+	defer ctx.Close()
+
+	repoName := reflection.GetTypeName(reflect.TypeOf(&introRepoImpl{}))
+	serviceName := reflection.GetTypeName(reflect.TypeOf(&introService{}))
+	reportName := reflection.GetTypeName(reflect.TypeOf(&introReport{}))
+	repoIface := reflection.GetTypeName(reflect.TypeOf((*introRepo)(nil)).Elem())
+
+	repo, ok := ctx.DescribeBean(repoName)
+	if !ok {
+		t.Fatal("expect repo described")
+	}
+	if !repo.Primary || !reflect.DeepEqual(repo.Qualifiers, []string{"db"}) || !repo.Initialized || repo.Kind != bean.KindObject {
+		t.Fatalf("unexpected repo descriptor %+v", repo)
+	}
+	if !reflect.DeepEqual(repo.Dependents, []string{serviceName}) {
+		t.Fatalf("expect service depends on repo, got %v", repo.Dependents)
+	}
+	// 按interface注入后以interface名称缓存，可以通过别名查找
+	if byAlias, ok := ctx.DescribeBean(repoIface); !ok || byAlias.Name != repoName {
+		t.Fatalf("expect repo found by alias %s, got %+v", repoIface, byAlias)
+	}
+
+	service, _ := ctx.DescribeBean(serviceName)
+	if !reflect.DeepEqual(service.Dependencies, []string{repoName}) {
+		t.Fatalf("expect service dependencies [%s], got %v", repoName, service.Dependencies)
+	}
+	report, _ := ctx.DescribeBean(reportName)
+	if !report.Lazy || report.Initialized {
+		t.Fatalf("expect lazy bean not initialized, got %+v", report)
+	}
+	if _, ok := ctx.DescribeBean("notExists"); ok {
+		t.Fatal("expect not found")
+	}
+
+	// 按初始化顺序排列，被依赖的bean在前
+	beans := ctx.DescribeBeans()
+	pos := map[string]int{}
+	for i, b := range beans {
+		pos[b.Name] = i
+	}
+	if pos[repoName] > pos[serviceName] {
+		t.Fatalf("expect repo before service, got %v", beans)
+	}
+}
+
+func TestWriteBeans(t *testing.T) {
+	beans := []BeanDescriptor{
+		{Name: "repo", Type: "*repo", Kind: bean.KindObject, Scope: bean.ScopeSingleton, Initialized: true},
+		{Name: "service", Type: "*service", Kind: bean.KindFunction, Scope: bean.ScopeSingleton, Dependencies: []string{"repo"}, Lazy: true},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteBeansJSON(buf, beans); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []BeanDescriptor
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, beans) {
+		t.Fatalf("expect JSON round trip, got %+v", decoded)
+	}
+
+	buf.Reset()
+	if err := WriteBeansDOT(buf, beans); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, s := range []string{
+		"digraph beans {",
+		`"service" -> "repo";`,
+		`"repo" [label="repo\n*repo\nobject singleton"];`,
+		`style=dashed`,
+	} {
+		if !strings.Contains(dot, s) {
+			t.Fatalf("expect DOT contains %s, got:\n%s", s, dot)
+		}
+	}
+}
+
+func TestExportBeans(t *testing.T) {
+	for _, ext := range []string{".json", ".dot"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "beans"+ext)
+			ctx := startIntrospectionContext(t, map[string]interface{}{"beansGraph": path})
+			defer ctx.Close()
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ext == ".json" {
+				var beans []BeanDescriptor
+				if err := json.Unmarshal(data, &beans); err != nil || len(beans) == 0 {
+					t.Fatalf("expect beans JSON, got %v %s", err, data)
+				}
+			} else if !strings.HasPrefix(string(data), "digraph beans {") {
+				t.Fatalf("expect beans DOT, got %s", data)
+			}
+		})
+	}
+}
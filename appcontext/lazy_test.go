@@ -0,0 +1,126 @@
+package appcontext
+
+import (
+	"errors"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lazyDep struct{}
+
+type lazyService struct {
+	Dep *lazyDep `inject:""`
+
+	lock  sync.Mutex
+	ready bool
+}
+
+func (s *lazyService) BeanAfterSet() error {
+	time.Sleep(20 * time.Millisecond)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.ready = true
+	return nil
+}
+
+func (s *lazyService) isReady() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.ready && s.Dep != nil
+}
+
+type failingLazy struct{}
+
+func (s *failingLazy) BeanAfterSet() error {
+	return errors.New("failingLazy init failed")
+}
+
+func TestLazyBeanConcurrentGet(t *testing.T) {
+	ctx := newTestContext(t, nil)
+	defer ctx.Close()
+	_ = ctx.RegisterBean(&lazyDep{})
+	_ = ctx.RegisterBean(&lazyService{}, bean.SetLazy())
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	name := reflection.GetTypeName(reflect.TypeOf(&lazyService{}))
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			o, ok := ctx.GetBean(name)
+			if !ok {
+				t.Error("lazy bean not found")
+				return
+			}
+			if !o.(*lazyService).isReady() {
+				t.Error("got lazy bean before initialization finished")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLazyBeanInitErrorAfterStart(t *testing.T) {
+	for _, failFast := range []string{"false", "true"} {
+		t.Run("failFast="+failFast, func(t *testing.T) {
+			ctx := newTestContext(t, map[string]interface{}{"failFast": failFast})
+			defer ctx.Close()
+			_ = ctx.RegisterBean(&failingLazy{}, bean.SetLazy())
+			if err := ctx.Start(); err != nil {
+				t.Fatal(err)
+			}
+			name := reflection.GetTypeName(reflect.TypeOf(&failingLazy{}))
+			// 每次获取都返回初始化的错误
+			for i := 0; i < 2; i++ {
+				func() {
+					defer func() {
+						if recover() == nil {
+							t.Fatal("expect panic when getting failed lazy bean")
+						}
+					}()
+					ctx.GetBean(name)
+				}()
+			}
+		})
+	}
+}
+
+type lazyCycleA struct {
+	B *lazyCycleB `inject:""`
+}
+
+type lazyCycleB struct {
+	A *lazyCycleA `inject:""`
+}
+
+func TestLazyBeanCycle(t *testing.T) {
+	ctx := newTestContext(t, nil)
+	defer ctx.Close()
+	_ = ctx.RegisterBean(&lazyCycleA{}, bean.SetLazy())
+	_ = ctx.RegisterBean(&lazyCycleB{}, bean.SetLazy())
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan *lazyCycleA, 1)
+	go func() {
+		o, _ := ctx.GetBean(reflection.GetTypeName(reflect.TypeOf(&lazyCycleA{})))
+		done <- o.(*lazyCycleA)
+	}()
+	select {
+	case a := <-done:
+		// 初始化链上的循环依赖注入未完成初始化的对象
+		if a.B == nil || a.B.A != a {
+			t.Fatal("expect lazy cycle injected")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("lazy cycle deadlocked")
+	}
+}
@@ -0,0 +1,138 @@
+package appcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	errors2 "github.com/ydx1011/gopher-core/errors"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// 需要在所有bean初始化完成后启动、在销毁前停止的长期运行组件（如HTTP服务、消息消费者），
+// 实现该接口的单例bean会被ApplicationContext自动发现：
+// 启动时按Phase从小到大启动，关闭时在BeanDestroy之前按Phase从大到小停止，Phase相同的按依赖顺序启动（逆序停止）
+type Lifecycle interface {
+	// 启动组件，不应阻塞，长期运行的任务需要在协程中执行
+	Start(ctx context.Context) error
+
+	// 停止组件，ctx的期限为gopher.application.shutdownTimeout剩余的时间
+	Stop(ctx context.Context) error
+
+	// 是否运行中，只有运行中的组件才会被停止
+	IsRunning() bool
+
+	// 启动阶段，越小越先启动、越后停止
+	Phase() int
+}
+
+// Lifecycle可选实现的接口，IsAutoStartup返回false时ApplicationContext启动时不自动启动该组件，
+// 可通过LifecycleManager的StartLifecycles手动启动
+type AutoStartup interface {
+	IsAutoStartup() bool
+}
+
+// 管理Lifecycle组件的ApplicationContext
+type LifecycleManager interface {
+	// 启动所有未运行的Lifecycle组件（包括不自动启动的组件），只能在启动完成后调用
+	StartLifecycles(ctx context.Context) error
+
+	// 停止所有运行中的Lifecycle组件
+	StopLifecycles(ctx context.Context) error
+}
+
+type lifecycleBean struct {
+	name      string
+	lifecycle Lifecycle
+}
+
+// 按Phase排序的Lifecycle组件，Phase相同时保持依赖顺序
+func (ctx *defaultApplicationContext) getLifecycles() []lifecycleBean {
+	if ctx.graph == nil {
+		return nil
+	}
+	var ret []lifecycleBean
+	for _, n := range ctx.graph.order {
+		if o, ok := beanInstance(n.def); ok {
+			if l, ok := o.(Lifecycle); ok {
+				ret = append(ret, lifecycleBean{name: n.name, lifecycle: l})
+			}
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].lifecycle.Phase() < ret[j].lifecycle.Phase()
+	})
+	return ret
+}
+
+// 启动Lifecycle组件，autoOnly为true时跳过不自动启动的组件。
+// 严格模式下某个组件启动失败时不再启动后续组件
+func (ctx *defaultApplicationContext) startLifecycles(c context.Context, autoOnly bool) errors2.Errors {
+	var errs errors2.Errors
+	for _, b := range ctx.getLifecycles() {
+		if b.lifecycle.IsRunning() {
+			continue
+		}
+		if a, ok := b.lifecycle.(AutoStartup); ok && autoOnly && !a.IsAutoStartup() {
+			continue
+		}
+		if err := b.lifecycle.Start(c); err != nil {
+			_ = errs.AddError(newBeanError(PhaseLifecycle, b.name, reflect.TypeOf(b.lifecycle), err))
+			if ctx.failFast {
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// 按Phase从大到小停止运行中的组件，超过c的期限的组件记录到关闭报告中
+func (ctx *defaultApplicationContext) stopLifecycles(c context.Context) errors2.Errors {
+	var errs errors2.Errors
+	lifecycles := ctx.getLifecycles()
+	for i := len(lifecycles) - 1; i >= 0; i-- {
+		b := lifecycles[i]
+		if !b.lifecycle.IsRunning() {
+			continue
+		}
+		start := time.Now()
+		err := callWithTimeout(c, 0, b.lifecycle.Stop)
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			ctx.addShutdownTimeout(b.name, reflect.TypeOf(b.lifecycle), time.Since(start))
+			continue
+		}
+		_ = errs.AddError(fmt.Errorf("Stop lifecycle bean [%s] failed: %v ", b.name, err))
+	}
+	return errs
+}
+
+// 启动时自动启动组件，严格模式下启动失败时停止已启动的组件
+func (ctx *defaultApplicationContext) autoStartLifecycles() errors2.Errors {
+	errs := ctx.startLifecycles(context.Background(), true)
+	if !errs.Empty() && ctx.failFast {
+		ctx.logErrors(ctx.stopLifecycles(context.Background()))
+	}
+	return errs
+}
+
+func (ctx *defaultApplicationContext) StartLifecycles(c context.Context) error {
+	if atomic.LoadInt32(&ctx.curState) != statusInitialized {
+		return errors.New("Application Context is not started. ")
+	}
+	if errs := ctx.startLifecycles(c, false); !errs.Empty() {
+		return errs
+	}
+	return nil
+}
+
+func (ctx *defaultApplicationContext) StopLifecycles(c context.Context) error {
+	if errs := ctx.stopLifecycles(c); !errs.Empty() {
+		return errs
+	}
+	return nil
+}
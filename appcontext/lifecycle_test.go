@@ -0,0 +1,137 @@
+package appcontext
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testLifecycle struct {
+	name      string
+	phase     int
+	manual    bool
+	failStart bool
+	running   bool
+	rec       *orderRecorder
+}
+
+func (l *testLifecycle) Start(ctx context.Context) error {
+	if l.failStart {
+		return errors.New("start failed")
+	}
+	l.running = true
+	l.rec.add("start " + l.name)
+	return nil
+}
+
+func (l *testLifecycle) Stop(ctx context.Context) error {
+	l.running = false
+	l.rec.add("stop " + l.name)
+	return nil
+}
+
+func (l *testLifecycle) IsRunning() bool {
+	return l.running
+}
+
+func (l *testLifecycle) Phase() int {
+	return l.phase
+}
+
+func (l *testLifecycle) IsAutoStartup() bool {
+	return !l.manual
+}
+
+func registerLifecycles(ctx *defaultApplicationContext, lifecycles ...*testLifecycle) {
+	for _, l := range lifecycles {
+		_ = ctx.RegisterBeanByName(l.name, l)
+	}
+}
+
+func TestLifecyclePhaseOrder(t *testing.T) {
+	ctx := newTestContext(t, nil)
+	rec := &orderRecorder{}
+	registerLifecycles(ctx,
+		&testLifecycle{name: "web", phase: 10, rec: rec},
+		&testLifecycle{name: "db", phase: 0, rec: rec},
+		&testLifecycle{name: "cache", phase: 5, rec: rec},
+		&testLifecycle{name: "worker", phase: 5, rec: rec},
+	)
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	_ = ctx.Close()
+
+	// Phase相同的按注册（依赖）顺序启动，停止时逆序
+	expect := "start db,start cache,start worker,start web,stop web,stop worker,stop cache,stop db"
+	if got := strings.Join(rec.names, ","); got != expect {
+		t.Fatalf("expect %s, got %s", expect, got)
+	}
+}
+
+func TestLifecycleAutoStartup(t *testing.T) {
+	ctx := newTestContext(t, nil)
+	defer ctx.Close()
+	rec := &orderRecorder{}
+	auto := &testLifecycle{name: "auto", rec: rec}
+	manual := &testLifecycle{name: "manual", manual: true, rec: rec}
+	registerLifecycles(ctx, auto, manual)
+
+	if err := ctx.StartLifecycles(context.Background()); err == nil {
+		t.Fatal("expect error before context started")
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if !auto.running || manual.running {
+		t.Fatalf("expect only auto startup component running, got auto %v manual %v", auto.running, manual.running)
+	}
+	if err := ctx.StartLifecycles(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !manual.running {
+		t.Fatal("expect manual component started")
+	}
+	if err := ctx.StopLifecycles(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if auto.running || manual.running {
+		t.Fatal("expect all components stopped")
+	}
+	expect := "start auto,start manual,stop manual,stop auto"
+	if got := strings.Join(rec.names, ","); got != expect {
+		t.Fatalf("expect %s, got %s", expect, got)
+	}
+}
+
+func TestLifecycleStartFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		failFast string
+		expect   string
+	}{
+		// 严格模式下不再启动后续组件，并停止已启动的组件
+		{name: "failFast", failFast: "true", expect: "start db,stop db"},
+		{name: "lenient", failFast: "false", expect: "start db,start job"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t, map[string]interface{}{"failFast": tt.failFast})
+			defer ctx.Close()
+			rec := &orderRecorder{}
+			registerLifecycles(ctx,
+				&testLifecycle{name: "db", phase: 0, rec: rec},
+				&testLifecycle{name: "web", phase: 10, failStart: true, rec: rec},
+				&testLifecycle{name: "job", phase: 20, rec: rec},
+			)
+			err := ctx.Start()
+			if (err != nil) != (tt.failFast == "true") {
+				t.Fatalf("unexpected start result: %v", err)
+			}
+			if got := strings.Join(rec.names, ","); got != tt.expect {
+				t.Fatalf("expect %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
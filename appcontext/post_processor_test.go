@@ -0,0 +1,69 @@
+package appcontext
+
+import (
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type ppDep struct {
+	prefix string
+}
+
+type ppTarget struct {
+	name string
+}
+
+type ppTarget2 struct {
+	name string
+}
+
+type recordingPostProcessor struct {
+	Dep *ppDep `inject:""`
+
+	lock      sync.Mutex
+	processed map[string]bool
+}
+
+func (p *recordingPostProcessor) BeforeInitialization(name string, o interface{}) (interface{}, error) {
+	// 依赖未注入时会panic
+	_ = p.Dep.prefix
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.processed[name] = true
+	return nil, nil
+}
+
+func (p *recordingPostProcessor) AfterInitialization(name string, o interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func TestPostProcessorInitializedFirst(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		t.Run("workers="+strconv.Itoa(workers), func(t *testing.T) {
+			ctx := newTestContext(t, map[string]interface{}{"failFast": "true"})
+			defer ctx.Close()
+			ctx.injectWorkers = workers
+			pp := &recordingPostProcessor{processed: map[string]bool{}}
+			// 先于处理器及其依赖注册的bean
+			_ = ctx.RegisterBeanByName("target", &ppTarget{})
+			_ = ctx.RegisterBeanByName("target2", &ppTarget2{})
+			_ = ctx.RegisterBeanByName("pp", pp)
+			_ = ctx.RegisterBean(&ppDep{prefix: "x"})
+			if err := ctx.Start(); err != nil {
+				t.Fatal(err)
+			}
+			if pp.Dep == nil {
+				t.Fatal("expect post processor injected")
+			}
+			depName := reflection.GetTypeName(reflect.TypeOf(&ppDep{}))
+			for name, expect := range map[string]bool{"target": true, "target2": true, depName: false, "pp": false} {
+				if pp.processed[name] != expect {
+					t.Fatalf("bean %s processed: %v, expect %v", name, pp.processed[name], expect)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package appcontext
+
+import (
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/processor"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+type prototypeValueBean struct {
+	Name string `value:"gopher.application.testName"`
+
+	initialized bool
+}
+
+func (b *prototypeValueBean) BeanAfterSet() error {
+	b.initialized = true
+	return nil
+}
+
+type countingPostProcessor struct {
+	before int32
+	after  int32
+}
+
+func (p *countingPostProcessor) BeforeInitialization(name string, o interface{}) (interface{}, error) {
+	if _, ok := o.(*prototypeValueBean); ok {
+		atomic.AddInt32(&p.before, 1)
+	}
+	return nil, nil
+}
+
+func (p *countingPostProcessor) AfterInitialization(name string, o interface{}) (interface{}, error) {
+	if _, ok := o.(*prototypeValueBean); ok {
+		atomic.AddInt32(&p.after, 1)
+	}
+	return nil, nil
+}
+
+func TestPrototypeInstanceProcessed(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope string
+	}{
+		{name: "prototype", scope: bean.ScopePrototype},
+		{name: "trackedPrototype", scope: bean.ScopeTrackedPrototype},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t, map[string]interface{}{"testName": "proto"})
+			defer ctx.Close()
+			pp := &countingPostProcessor{}
+			_ = ctx.RegisterBean(processor.NewValueProcessor(processor.OptSetValueTag("", "value")))
+			_ = ctx.RegisterBean(pp)
+			_ = ctx.RegisterBean(func() *prototypeValueBean {
+				return &prototypeValueBean{}
+			}, bean.SetScope(tt.scope))
+			if err := ctx.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			name := reflection.GetTypeName(reflect.TypeOf(&prototypeValueBean{}))
+			var last *prototypeValueBean
+			for i := 0; i < 2; i++ {
+				o, ok := ctx.GetBean(name)
+				if !ok {
+					t.Fatal("prototype bean not found")
+				}
+				b := o.(*prototypeValueBean)
+				if b == last {
+					t.Fatal("expect a new object for each get")
+				}
+				last = b
+				if b.Name != "proto" {
+					t.Fatalf("expect value tag filled, got %q", b.Name)
+				}
+				if !b.initialized {
+					t.Fatal("expect BeanAfterSet called")
+				}
+			}
+			if pp.before != 2 || pp.after != 2 {
+				t.Fatalf("expect post processor called twice, got %d %d", pp.before, pp.after)
+			}
+		})
+	}
+}
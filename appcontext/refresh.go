@@ -0,0 +1,164 @@
+package appcontext
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/config"
+	errors2 "github.com/ydx1011/gopher-core/errors"
+	"github.com/ydx1011/gopher-core/processor"
+	"github.com/ydx1011/yfig"
+	"reflect"
+	"sync/atomic"
+)
+
+// 使用新的配置刷新context，只能在启动完成后调用：
+// 1、计算发生变化的属性，没有变化时直接返回；
+// 2、按依赖顺序对标记为可刷新（bean.SetRefreshable）的bean重新绑定配置属性（bean.BindConfig）并执行processor.RefreshProcessor；
+// 3、发布ConfigChangedEvent。
+func (ctx *defaultApplicationContext) Refresh(conf yfig.Properties) error {
+	if conf == nil {
+		return errors.New("Properties cannot be nil. ")
+	}
+	if atomic.LoadInt32(&ctx.curState) != statusInitialized {
+		return errors.New("Application Context is not started, cannot refresh. ")
+	}
+
+	ctx.refreshLock.Lock()
+	defer ctx.refreshLock.Unlock()
+
+	old := ctx.GetProperties()
+	keys := config.Diff(old, conf)
+	if len(keys) == 0 {
+		return nil
+	}
+	ctx.configLock.Lock()
+	ctx.config = conf
+	ctx.configLock.Unlock()
+
+	bindings := map[string]*bean.ConfigBinding{}
+	ctx.bindingLock.Lock()
+	for _, b := range ctx.configBindings {
+		bindings[b.name] = b.binding
+	}
+	ctx.bindingLock.Unlock()
+
+	var errs errors2.Errors
+	for _, n := range ctx.refreshNodes() {
+		if info, ok := bean.GetRegisterInfo(ctx.container, n.name); !ok || !info.Refreshable {
+			continue
+		}
+		if b, ok := bindings[n.name]; ok {
+			if err := rebind(conf, b); err != nil {
+				_ = errs.AddError(newBeanError(PhaseRefresh, n.name, n.def.Type(), err))
+				continue
+			}
+		}
+		o, ok := beanInstance(n.def)
+		if !ok {
+			continue
+		}
+		for _, err := range ctx.refreshBean(conf, o) {
+			_ = errs.AddError(newBeanError(PhaseRefresh, n.name, n.def.Type(), err))
+		}
+	}
+
+	ctx.logger.Infof("Config changed: %v\n", keys)
+	if !ctx.disableEvent {
+		e := &ConfigChangedEvent{
+			ChangedKeys:   keys,
+			OldProperties: old,
+		}
+		e.ResetOccurredTime()
+		e.ctx = ctx
+		if err := ctx.PublishEvent(e); err != nil {
+			_ = errs.AddError(err)
+		}
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// 获得容器中当前所有的bean：启动时已在依赖图中的bean按依赖顺序在前，启动后注册的bean按容器顺序在后
+func (ctx *defaultApplicationContext) refreshNodes() []*beanNode {
+	inGraph := make(map[bean.Definition]bool, len(ctx.graph.order))
+	for _, n := range ctx.graph.order {
+		inGraph[n.def] = true
+	}
+	current := map[bean.Definition]bool{}
+	var added []*beanNode
+	ctx.container.Scan(func(key string, value bean.Definition) bool {
+		if current[value] {
+			return true
+		}
+		current[value] = true
+		if !inGraph[value] {
+			added = append(added, &beanNode{name: key, def: value})
+		}
+		return true
+	})
+
+	ret := make([]*beanNode, 0, len(current))
+	for _, n := range ctx.graph.order {
+		// 跳过已从容器中移除的bean
+		if current[n.def] {
+			ret = append(ret, n)
+		}
+	}
+	return append(ret, added...)
+}
+
+func (ctx *defaultApplicationContext) refreshBean(conf yfig.Properties, o interface{}) errors2.Errors {
+	ctx.processorsLock.Lock()
+	defer ctx.processorsLock.Unlock()
+
+	var errs errors2.Errors
+	for _, p := range ctx.processors {
+		if rp, ok := p.(processor.RefreshProcessor); ok {
+			if err := rp.Refresh(conf, o); err != nil {
+				_ = errs.AddError(err)
+			}
+		}
+	}
+	return errs
+}
+
+// 获得bean的原对象，只支持对象及单例，未初始化的延迟初始化对象返回false
+func beanInstance(d bean.Definition) (o interface{}, ok bool) {
+	if ld, ok := d.(bean.LazyDefinition); ok {
+		if !ld.Initialized() {
+			return nil, false
+		}
+		d = ld.Unwrap()
+	}
+	if d.IsObject() {
+		return d.Interface(), true
+	}
+	if sd, ok := d.(bean.ScopedDefinition); !ok || sd.Scope().Name() != bean.ScopeSingleton {
+		return nil, false
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			o, ok = nil, false
+		}
+	}()
+	v := d.Value()
+	if !v.IsValid() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// 使用新的配置重新绑定，绑定或校验失败时保持原有的值
+func rebind(conf yfig.Properties, b *bean.ConfigBinding) error {
+	target := reflect.ValueOf(b.Target).Elem()
+	tmp := reflect.New(target.Type())
+	tmp.Elem().Set(target)
+	if err := config.Bind(conf, b.Prefix, tmp.Interface()); err != nil {
+		return fmt.Errorf("Rebind config [%s] failed, keep the old value: %v ", b.Prefix, err)
+	}
+	target.Set(tmp.Elem())
+	return nil
+}
@@ -0,0 +1,107 @@
+package appcontext
+
+import (
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/config"
+	"github.com/ydx1011/gopher-core/processor"
+	"github.com/ydx1011/yfig"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type refreshTestProcessor struct {
+	conf yfig.Properties
+}
+
+func (p *refreshTestProcessor) Init(conf yfig.Properties, container bean.Container) error {
+	p.conf = conf
+	return nil
+}
+
+func (p *refreshTestProcessor) Classify(o interface{}) (bool, error) {
+	return false, nil
+}
+
+func (p *refreshTestProcessor) Process() error {
+	return nil
+}
+
+func (p *refreshTestProcessor) BeanDestroy() error {
+	return nil
+}
+
+// 配置刷新与启动后读取配置的操作并发执行，使用-race检查
+func TestRefreshConcurrentConfigReaders(t *testing.T) {
+	ctx := newTestContext(t, nil)
+	defer ctx.Close()
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			err := ctx.Refresh(config.NewProperties(map[string]interface{}{
+				"gopher": map[string]interface{}{
+					"application": map[string]interface{}{
+						"bannerMode": "off",
+						"name":       "app" + strconv.Itoa(i),
+					},
+				},
+			}))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			if err := ctx.AddProcessor(&refreshTestProcessor{}); err != nil {
+				t.Error(err)
+				return
+			}
+			ctx.exportBeans()
+		}
+	}()
+	wg.Wait()
+}
+
+type refreshValueBean struct {
+	Name string `value:"gopher.application.testName"`
+}
+
+// 启动后注册的可刷新bean同样在配置刷新时重新处理
+func TestRefreshBeanRegisteredAfterStart(t *testing.T) {
+	ctx := newTestContext(t, map[string]interface{}{"testName": "old"})
+	defer ctx.Close()
+	_ = ctx.RegisterBean(processor.NewValueProcessor(processor.OptSetValueTag("", "value")))
+	started := &refreshValueBean{}
+	_ = ctx.RegisterBeanByName("started", started, bean.SetRefreshable())
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	late := &refreshValueBean{}
+	if err := ctx.RegisterBeanByName("late", late, bean.SetRefreshable()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ctx.Refresh(config.NewProperties(map[string]interface{}{
+		"gopher": map[string]interface{}{
+			"application": map[string]interface{}{
+				"bannerMode": "off",
+				"testName":   "new",
+			},
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started.Name != "new" || late.Name != "new" {
+		t.Fatalf("expect refreshed values, got %q %q", started.Name, late.Name)
+	}
+}
@@ -0,0 +1,169 @@
+package appcontext
+
+import (
+	"errors"
+	"github.com/ydx1011/gopher-core/bean"
+	errors2 "github.com/ydx1011/gopher-core/errors"
+	"github.com/ydx1011/yfig"
+	"sync/atomic"
+	"testing"
+)
+
+type startTestBean struct {
+	destroyed bool
+}
+
+func (b *startTestBean) BeanDestroy() error {
+	b.destroyed = true
+	return nil
+}
+
+type failingFactoryBean struct{}
+
+func TestStartReturnsCreationError(t *testing.T) {
+	tests := []struct {
+		name     string
+		failFast string
+		opts     []bean.RegisterOpt
+	}{
+		{name: "lenient", failFast: "false"},
+		{name: "failFast", failFast: "true"},
+		{name: "lenient lazy", failFast: "false", opts: []bean.RegisterOpt{bean.SetLazy()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t, map[string]interface{}{"failFast": tt.failFast})
+			defer ctx.Close()
+			ok := &startTestBean{}
+			_ = ctx.RegisterBean(ok)
+			_ = ctx.RegisterBean(func(b *startTestBean) (*failingFactoryBean, error) {
+				return nil, errors.New("open failed")
+			}, tt.opts...)
+			// 被依赖以保证延迟初始化的bean在启动时初始化
+			_ = ctx.RegisterBean(func(b *failingFactoryBean) *struct{ v int } {
+				return &struct{ v int }{}
+			})
+
+			err := ctx.Start()
+			if err == nil {
+				t.Fatal("expect creation error")
+			}
+			var ce *BeanCreationError
+			found := false
+			for _, e := range err.(errors2.Errors) {
+				if errors.As(e, &ce) {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("expect BeanCreationError, got %v", err)
+			}
+			if !ok.destroyed {
+				t.Fatal("expect initialized beans rolled back")
+			}
+		})
+	}
+}
+
+type conditionTestBean struct{}
+
+type failingInitBean struct{}
+
+func (b *failingInitBean) BeanAfterSet() error {
+	return errors.New("init failed")
+}
+
+func TestStartFailFastResetsState(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(ctx *defaultApplicationContext)
+	}{
+		{
+			name: "condition",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(&conditionTestBean{})
+				// 同名bean条件满足后注册失败
+				_ = ctx.RegisterBean(&conditionTestBean{}, bean.OnMissingBean(&startTestBean{}))
+			},
+		},
+		{
+			name: "afterSet",
+			register: func(ctx *defaultApplicationContext) {
+				_ = ctx.RegisterBean(&failingInitBean{})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t, map[string]interface{}{"failFast": "true"})
+			defer ctx.Close()
+			tt.register(ctx)
+			if err := ctx.Start(); err == nil {
+				t.Fatal("expect start error")
+			}
+			if atomic.LoadInt32(&ctx.curState) != statusNone {
+				t.Fatalf("expect state reset, got %d", ctx.curState)
+			}
+		})
+	}
+}
+
+type flakyProcessor struct {
+	fails int32
+}
+
+func (p *flakyProcessor) Init(conf yfig.Properties, container bean.Container) error {
+	return nil
+}
+
+func (p *flakyProcessor) Classify(o interface{}) (bool, error) {
+	return false, nil
+}
+
+func (p *flakyProcessor) Process() error {
+	if atomic.AddInt32(&p.fails, -1) >= 0 {
+		return errors.New("process failed")
+	}
+	return nil
+}
+
+func (p *flakyProcessor) BeanDestroy() error {
+	return nil
+}
+
+func TestStartRetryKeepsConditionalBeans(t *testing.T) {
+	ctx := newTestContext(t, map[string]interface{}{"failFast": "true"})
+	defer ctx.Close()
+	_ = ctx.RegisterBean(&flakyProcessor{fails: 1})
+	_ = ctx.RegisterBean(&conditionTestBean{}, bean.OnBean(&startTestBean{}))
+
+	err := ctx.Start()
+	if err == nil {
+		t.Fatal("expect process error")
+	}
+	var be *BeanError
+	if !errors.As(err.(errors2.Errors)[0], &be) || be.Phase != PhaseProcess {
+		t.Fatalf("expect process BeanError, got %v", err)
+	}
+	// 启动失败后补充注册依赖的bean，再次启动时重新判断条件
+	_ = ctx.RegisterBean(&startTestBean{})
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if !ctx.GetBeanByType(new(*conditionTestBean)) {
+		t.Fatal("expect conditional bean registered after retry")
+	}
+	if len(ctx.GetConditionReport()) != 1 {
+		t.Fatalf("expect one condition outcome, got %v", ctx.GetConditionReport())
+	}
+}
+
+func TestStartLenientProcessError(t *testing.T) {
+	ctx := newTestContext(t, map[string]interface{}{"failFast": "false"})
+	defer ctx.Close()
+	_ = ctx.RegisterBean(&flakyProcessor{fails: 1})
+	// 非严格模式下处理器的错误记录日志后继续启动
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,211 @@
+package appcontext
+
+import (
+	"fmt"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// 启动报告中列出的bean初始化耗时阈值（如50ms），默认为10ms，设置为off时不输出启动报告日志
+	KeyStartupReportThreshold     = "gopher.application.startupReport.threshold"
+	defaultStartupReportThreshold = 10 * time.Millisecond
+)
+
+// 启动阶段的耗时
+type PhaseTiming struct {
+	// 阶段名称，见Phase*常量
+	Phase string `json:"phase"`
+
+	// 耗时，JSON中单位为纳秒
+	Duration time.Duration `json:"duration"`
+
+	// 是否为各bean在该阶段耗时之和（注入、分类、方法注入及BeanAfterSet按bean逐个执行，并行初始化时可能大于实际耗时）
+	Cumulative bool `json:"cumulative,omitempty"`
+}
+
+// bean的初始化及销毁耗时
+type BeanTiming struct {
+	// bean名称
+	Name string `json:"name"`
+
+	// bean类型名称
+	Type string `json:"type"`
+
+	// 各初始化步骤（PhaseInject、PhaseClassify、PhaseFunctionInject、PhaseAfterSet）的耗时
+	Steps map[string]time.Duration `json:"steps,omitempty"`
+
+	// 初始化总耗时
+	Init time.Duration `json:"init"`
+
+	// 销毁耗时，Close之前为0
+	Destroy time.Duration `json:"destroy,omitempty"`
+}
+
+// 启动报告
+type StartupReport struct {
+	// Start总耗时
+	Total time.Duration `json:"total"`
+
+	// 按执行顺序排列的各阶段耗时
+	Phases []PhaseTiming `json:"phases"`
+
+	// 按初始化耗时从大到小排列的bean耗时
+	Beans []BeanTiming `json:"beans"`
+}
+
+// 提供启动报告的ApplicationContext
+type StartupReporter interface {
+	// 获得启动报告，在Start之后可用，Close之后包含bean的销毁耗时
+	GetStartupReport() StartupReport
+}
+
+// 启动完成后（ContextStartedEvent之前）发布，包含启动报告
+type ApplicationStartupEvent struct {
+	ApplicationContextEvent
+
+	Report StartupReport
+}
+
+// bean逐个执行的初始化步骤，其耗时在报告中汇总为阶段耗时
+var beanSteps = []string{PhaseInject, PhaseClassify, PhaseFunctionInject, PhaseAfterSet}
+
+func (ctx *defaultApplicationContext) timePhase(phase string, f func()) {
+	start := time.Now()
+	f()
+	elapsed := time.Since(start)
+
+	ctx.timingLock.Lock()
+	defer ctx.timingLock.Unlock()
+	ctx.phaseTimings = append(ctx.phaseTimings, PhaseTiming{Phase: phase, Duration: elapsed})
+}
+
+func (ctx *defaultApplicationContext) beanTiming(name string, t reflect.Type) *BeanTiming {
+	ret, ok := ctx.beanTimings[name]
+	if !ok {
+		ret = &BeanTiming{
+			Name:  name,
+			Type:  reflection.GetTypeName(t),
+			Steps: map[string]time.Duration{},
+		}
+		ctx.beanTimings[name] = ret
+	}
+	return ret
+}
+
+func (ctx *defaultApplicationContext) recordStep(n *beanNode, step string, d time.Duration) {
+	ctx.timingLock.Lock()
+	defer ctx.timingLock.Unlock()
+
+	t := ctx.beanTiming(n.name, n.def.Type())
+	t.Steps[step] += d
+	t.Init += d
+}
+
+func (ctx *defaultApplicationContext) recordDestroy(name string, t reflect.Type, d time.Duration) {
+	ctx.timingLock.Lock()
+	defer ctx.timingLock.Unlock()
+
+	ctx.beanTiming(name, t).Destroy += d
+}
+
+func (ctx *defaultApplicationContext) initDuration(name string) time.Duration {
+	ctx.timingLock.Lock()
+	defer ctx.timingLock.Unlock()
+
+	if t, ok := ctx.beanTimings[name]; ok {
+		return t.Init
+	}
+	return 0
+}
+
+func (ctx *defaultApplicationContext) GetStartupReport() StartupReport {
+	ctx.timingLock.Lock()
+	defer ctx.timingLock.Unlock()
+
+	ret := StartupReport{
+		Total: ctx.startupTime,
+	}
+	sums := map[string]time.Duration{}
+	for _, t := range ctx.beanTimings {
+		steps := make(map[string]time.Duration, len(t.Steps))
+		for k, v := range t.Steps {
+			steps[k] = v
+			sums[k] += v
+		}
+		b := *t
+		b.Steps = steps
+		ret.Beans = append(ret.Beans, b)
+	}
+	sort.SliceStable(ret.Beans, func(i, j int) bool {
+		if ret.Beans[i].Init != ret.Beans[j].Init {
+			return ret.Beans[i].Init > ret.Beans[j].Init
+		}
+		return ret.Beans[i].Name < ret.Beans[j].Name
+	})
+	for _, p := range ctx.phaseTimings {
+		ret.Phases = append(ret.Phases, p)
+		// 初始化阶段之后插入各步骤的汇总耗时
+		if p.Phase == PhaseInitialize {
+			for _, step := range beanSteps {
+				ret.Phases = append(ret.Phases, PhaseTiming{Phase: step, Duration: sums[step], Cumulative: true})
+			}
+		}
+	}
+	return ret
+}
+
+// 输出启动报告：各阶段耗时及初始化耗时超过阈值的bean
+func (ctx *defaultApplicationContext) logStartupReport(report StartupReport) {
+	v := ctx.GetProperties().Get(KeyStartupReportThreshold, "")
+	threshold := defaultStartupReportThreshold
+	if v == "off" || v == "false" {
+		return
+	}
+	if v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			ctx.logger.Warnf("%s must be duration, but get %s\n", KeyStartupReportThreshold, v)
+		} else {
+			threshold = d
+		}
+	}
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("Application started in %s\n", report.Total))
+	buf.WriteString(fmt.Sprintf("  %-20s %12s\n", "PHASE", "DURATION"))
+	for _, p := range report.Phases {
+		name := p.Phase
+		if p.Cumulative {
+			name = "  " + name
+		}
+		buf.WriteString(fmt.Sprintf("  %-20s %12s\n", name, p.Duration))
+	}
+	var slow []BeanTiming
+	for _, b := range report.Beans {
+		if b.Init >= threshold {
+			slow = append(slow, b)
+		}
+	}
+	if len(slow) > 0 {
+		buf.WriteString(fmt.Sprintf("  Beans initialized longer than %s:\n", threshold))
+		buf.WriteString(fmt.Sprintf("  %-12s %-12s %-12s %-12s %-12s %s\n", "INIT", PhaseInject, PhaseClassify, "funcInject", PhaseAfterSet, "BEAN"))
+		for _, b := range slow {
+			buf.WriteString(fmt.Sprintf("  %-12s %-12s %-12s %-12s %-12s %s\n", b.Init, b.Steps[PhaseInject], b.Steps[PhaseClassify],
+				b.Steps[PhaseFunctionInject], b.Steps[PhaseAfterSet], b.Name))
+		}
+	}
+	ctx.logger.Infoln(buf.String())
+}
+
+func (ctx *defaultApplicationContext) notifyStartup(report StartupReport) {
+	if ctx.disableEvent {
+		return
+	}
+	e := &ApplicationStartupEvent{Report: report}
+	e.ResetOccurredTime()
+	e.ctx = ctx
+	ctx.PublishEvent(e)
+}
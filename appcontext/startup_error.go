@@ -0,0 +1,94 @@
+package appcontext
+
+import (
+	"errors"
+	"fmt"
+	errors2 "github.com/ydx1011/gopher-core/errors"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+)
+
+// ApplicationContext启动阶段
+const (
+	PhaseCondition      = "condition"
+	PhaseAware          = "aware"
+	PhaseResolve        = "resolve"
+	PhaseBind           = "bind"
+	PhaseInitialize     = "initialize"
+	PhaseInject         = "inject"
+	PhaseClassify       = "classify"
+	PhaseFunctionInject = "functionInject"
+	PhaseAfterSet       = "afterSet"
+	PhaseProcess        = "process"
+	// 启动Lifecycle组件
+	PhaseLifecycle = "lifecycle"
+	// 配置更新（Refresh）
+	PhaseRefresh = "refresh"
+)
+
+// 启动过程中bean处理失败的错误
+type BeanError struct {
+	// 失败的启动阶段
+	Phase string
+
+	// bean名称
+	Name string
+
+	// bean类型名称
+	Type string
+
+	// 失败原因
+	Cause error
+}
+
+func newBeanError(phase string, name string, t reflect.Type, cause error) *BeanError {
+	ret := &BeanError{
+		Phase: phase,
+		Name:  name,
+		Cause: cause,
+	}
+	if t != nil {
+		ret.Type = reflection.GetTypeName(t)
+	}
+	return ret
+}
+
+func (e *BeanError) Error() string {
+	return fmt.Sprintf("Phase [%s] bean [%s] type [%s] failed: %v", e.Phase, e.Name, e.Type, e.Cause)
+}
+
+func (e *BeanError) Unwrap() error {
+	return e.Cause
+}
+
+// 创建bean的方法（如返回(T, error)的方法）返回错误或panic导致bean对象无法创建的错误，
+// 无论是否为严格模式，Start都会返回包含该错误的启动错误
+type BeanCreationError struct {
+	// bean名称
+	Name string
+
+	// 失败原因
+	Cause error
+}
+
+func (e *BeanCreationError) Error() string {
+	return fmt.Sprintf("Create bean [%s] failed: %v ", e.Name, e.Cause)
+}
+
+func (e *BeanCreationError) Unwrap() error {
+	return e.Cause
+}
+
+// 是否包含创建bean失败的错误
+func hasCreationError(errs errors2.Errors) bool {
+	for _, err := range errs {
+		var ce *BeanCreationError
+		if errors.As(err, &ce) {
+			return true
+		}
+		if es, ok := err.(errors2.Errors); ok && hasCreationError(es) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package appcontext
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type slowBean struct{}
+
+func (b *slowBean) BeanAfterSet() error {
+	time.Sleep(20 * time.Millisecond)
+	return nil
+}
+
+type slowDestroyBean struct{}
+
+func (b *slowDestroyBean) BeanDestroy() error {
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func TestStartupReport(t *testing.T) {
+	ctx := newTestContext(t, nil)
+	_ = ctx.RegisterBean(&slowBean{})
+	_ = ctx.RegisterBean(&slowDestroyBean{})
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	report := ctx.GetStartupReport()
+
+	// 阶段按执行顺序排列，初始化阶段之后为各步骤的汇总耗时
+	var phases []string
+	for _, p := range report.Phases {
+		if p.Cumulative {
+			phases = append(phases, "+"+p.Phase)
+		} else {
+			phases = append(phases, p.Phase)
+		}
+	}
+	expect := strings.Join([]string{PhaseCondition, PhaseAware, PhaseResolve, PhaseBind, PhaseInitialize,
+		"+" + PhaseInject, "+" + PhaseClassify, "+" + PhaseFunctionInject, "+" + PhaseAfterSet, PhaseProcess, PhaseLifecycle}, ",")
+	if got := strings.Join(phases, ","); got != expect {
+		t.Fatalf("expect phases %s, got %s", expect, got)
+	}
+	if report.Total < 20*time.Millisecond {
+		t.Fatalf("expect total contains slow bean, got %s", report.Total)
+	}
+
+	// bean按初始化耗时从大到小排列
+	if len(report.Beans) == 0 || !strings.HasSuffix(report.Beans[0].Name, "slowBean") {
+		t.Fatalf("expect slowBean first, got %v", report.Beans)
+	}
+	slow := report.Beans[0]
+	if slow.Steps[PhaseAfterSet] < 20*time.Millisecond || slow.Init < slow.Steps[PhaseAfterSet] {
+		t.Fatalf("expect afterSet timing recorded, got %+v", slow)
+	}
+
+	_ = ctx.Close()
+	for _, b := range ctx.GetStartupReport().Beans {
+		if strings.HasSuffix(b.Name, "slowDestroyBean") && b.Destroy < 10*time.Millisecond {
+			t.Fatalf("expect destroy timing recorded, got %+v", b)
+		}
+	}
+}
+
+func TestStartupEvent(t *testing.T) {
+	ctx := newTestContext(t, nil)
+	defer ctx.Close()
+	rec := &orderRecorder{}
+	reports := make(chan StartupReport, 1)
+	done := make(chan struct{})
+	ctx.AddListeners(func(e *ApplicationStartupEvent) {
+		rec.add("startup")
+		reports <- e.Report
+	})
+	ctx.AddListeners(func(e *ContextStartedEvent) {
+		rec.add("started")
+		close(done)
+	})
+	_ = ctx.RegisterBean(&slowBean{})
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expect ContextStartedEvent published")
+	}
+	select {
+	case report := <-reports:
+		if report.Total != ctx.GetStartupReport().Total {
+			t.Fatalf("expect event report equals context report, got %s", report.Total)
+		}
+	default:
+		t.Fatal("expect ApplicationStartupEvent published")
+	}
+	// ApplicationStartupEvent在ContextStartedEvent之前发布
+	if got := strings.Join(rec.names, ","); got != "startup,started" {
+		t.Fatalf("expect startup before started, got %s", got)
+	}
+}
@@ -0,0 +1,135 @@
+package appcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+	"time"
+)
+
+const (
+	KeyShutdownTimeout     = "gopher.application.shutdownTimeout"
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+// 关闭时超过期限（Lifecycle停止或销毁）的bean
+type ShutdownTimeout struct {
+	// bean名称
+	Name string
+
+	// bean类型名称
+	Type string
+
+	// 等待该bean销毁的时间
+	Elapsed time.Duration
+}
+
+// 提供关闭报告的ApplicationContext
+type ShutdownReporter interface {
+	// 获得关闭时超过销毁期限（bean.SetDestroyTimeout或gopher.application.shutdownTimeout）的bean，在Close之后可用
+	GetShutdownReport() []ShutdownTimeout
+}
+
+// 在ctx及timeout的期限内执行f，超过期限时不再等待f返回，返回ctx的错误（context.DeadlineExceeded）。
+// 没有期限时直接执行f。
+// 注意：Go无法终止协程，超过期限后执行f的协程会继续运行直到f返回，f应监听传入的ctx（ctx.Done()）并及时返回，
+// 只实现不带context的回调（bean.Initializing、bean.Disposable）无法感知期限，阻塞时该协程会一直存在
+func callWithTimeout(ctx context.Context, timeout time.Duration, f func(ctx context.Context) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if ctx.Done() == nil {
+		return f(ctx)
+	}
+	type result struct {
+		err error
+		r   interface{}
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{r: r}
+			}
+		}()
+		done <- result{err: f(ctx)}
+	}()
+	select {
+	case ret := <-done:
+		// 在调用方重新panic，与不设置期限时的行为一致
+		if ret.r != nil {
+			panic(ret.r)
+		}
+		return ret.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 初始化bean，超过bean.SetInitTimeout配置的时间时返回超时错误
+func (ctx *defaultApplicationContext) afterSet(n *beanNode) error {
+	info, _ := bean.GetRegisterInfo(ctx.container, n.name)
+	err := callWithTimeout(context.Background(), info.InitTimeout, func(c context.Context) error {
+		return bean.AfterSetWithContext(c, n.def)
+	})
+	if err != nil && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("BeanAfterSet exceeded init timeout %s: %w ", info.InitTimeout, err)
+	}
+	return err
+}
+
+// 销毁bean，超过期限时记录到关闭报告中并不再等待
+func (ctx *defaultApplicationContext) destroyBean(c context.Context, name string, d bean.Definition) {
+	info, _ := bean.GetRegisterInfo(ctx.container, name)
+	// 没有销毁回调的对象不受期限限制
+	if !hasDestroyCallback(d) {
+		c, info.DestroyTimeout = context.Background(), 0
+	}
+	start := time.Now()
+	err := callWithTimeout(c, info.DestroyTimeout, func(c context.Context) error {
+		return bean.DestroyWithContext(c, d)
+	})
+	if hasDestroyCallback(d) {
+		ctx.recordDestroy(name, d.Type(), time.Since(start))
+	}
+	if err == nil {
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		ctx.addShutdownTimeout(name, d.Type(), time.Since(start))
+		return
+	}
+	ctx.logger.Errorln(err)
+}
+
+func (ctx *defaultApplicationContext) addShutdownTimeout(name string, t reflect.Type, elapsed time.Duration) {
+	ctx.shutdownLock.Lock()
+	ctx.shutdownReport = append(ctx.shutdownReport, ShutdownTimeout{
+		Name:    name,
+		Type:    reflection.GetTypeName(t),
+		Elapsed: elapsed,
+	})
+	ctx.shutdownLock.Unlock()
+	ctx.logger.Warnf("Bean [%s] exceeded shutdown deadline after %s, skipped\n", name, elapsed)
+}
+
+// 方法创建的对象可能包含清理方法，总是认为有销毁回调
+func hasDestroyCallback(d bean.Definition) bool {
+	if !d.IsObject() {
+		return true
+	}
+	t := reflect.TypeOf(d.Interface())
+	return t != nil && (t.Implements(bean.DisposableType) || t.Implements(bean.DisposableContextType))
+}
+
+func (ctx *defaultApplicationContext) GetShutdownReport() []ShutdownTimeout {
+	ctx.shutdownLock.Lock()
+	defer ctx.shutdownLock.Unlock()
+
+	return ctx.shutdownReport
+}
@@ -0,0 +1,136 @@
+package appcontext
+
+import (
+	"context"
+	"errors"
+	"github.com/ydx1011/gopher-core/bean"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 超过期限时返回超时错误，传入f的ctx被取消以便f及时返回
+func TestCallWithTimeout(t *testing.T) {
+	returned := make(chan struct{})
+	err := callWithTimeout(context.Background(), 10*time.Millisecond, func(c context.Context) error {
+		defer close(returned)
+		<-c.Done()
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect deadline exceeded, got %v", err)
+	}
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("expect callback observed the deadline and returned")
+	}
+
+	if err := callWithTimeout(context.Background(), time.Second, func(c context.Context) error {
+		return errors.New("failed")
+	}); err == nil || err.Error() != "failed" {
+		t.Fatalf("expect callback error, got %v", err)
+	}
+}
+
+// 阻塞直到ctx被取消的bean
+type blockingBean struct {
+	initCtx      bool
+	destroyCtx   bool
+	blockInit    bool
+	blockDestroy bool
+}
+
+func (b *blockingBean) BeanAfterSet(ctx context.Context) error {
+	b.initCtx = true
+	if b.blockInit {
+		<-ctx.Done()
+	}
+	return nil
+}
+
+func (b *blockingBean) BeanDestroy(ctx context.Context) error {
+	b.destroyCtx = true
+	if b.blockDestroy {
+		<-ctx.Done()
+	}
+	return nil
+}
+
+type quickBean struct {
+	destroyed bool
+}
+
+func (b *quickBean) BeanDestroy() error {
+	b.destroyed = true
+	return nil
+}
+
+func TestContextCallbacks(t *testing.T) {
+	ctx := newTestContext(t, nil)
+	b := &blockingBean{}
+	_ = ctx.RegisterBean(b)
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	_ = ctx.Close()
+	if !b.initCtx || !b.destroyCtx {
+		t.Fatalf("expect context callbacks called, got init %v destroy %v", b.initCtx, b.destroyCtx)
+	}
+	if len(ctx.GetShutdownReport()) != 0 {
+		t.Fatalf("expect empty shutdown report, got %v", ctx.GetShutdownReport())
+	}
+}
+
+func TestInitTimeout(t *testing.T) {
+	ctx := newTestContext(t, map[string]interface{}{"failFast": "true"})
+	defer ctx.Close()
+	_ = ctx.RegisterBean(&blockingBean{blockInit: true}, bean.SetInitTimeout(10*time.Millisecond))
+	err := ctx.Start()
+	if err == nil || !strings.Contains(err.Error(), "exceeded init timeout") {
+		t.Fatalf("expect init timeout error, got %v", err)
+	}
+}
+
+func TestDestroyTimeout(t *testing.T) {
+	tests := []struct {
+		name  string
+		props map[string]interface{}
+		opts  []bean.RegisterOpt
+		// 先注册quickBean，使其在超时的bean之后销毁
+		quickFirst bool
+	}{
+		// bean超时后继续销毁其他bean
+		{name: "bean timeout", opts: []bean.RegisterOpt{bean.SetDestroyTimeout(10 * time.Millisecond)}, quickFirst: true},
+		// 全局关闭期限同样限制销毁，超过期限后不再等待剩余的bean
+		{name: "shutdown timeout", props: map[string]interface{}{"shutdownTimeout": "20ms"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t, tt.props)
+			quick := &quickBean{}
+			if tt.quickFirst {
+				_ = ctx.RegisterBean(quick)
+			}
+			_ = ctx.RegisterBean(&blockingBean{blockDestroy: true}, tt.opts...)
+			if !tt.quickFirst {
+				_ = ctx.RegisterBean(quick)
+			}
+			if err := ctx.Start(); err != nil {
+				t.Fatal(err)
+			}
+			start := time.Now()
+			_ = ctx.Close()
+			if time.Since(start) > time.Second {
+				t.Fatal("expect close not blocked by the timed out bean")
+			}
+			report := ctx.GetShutdownReport()
+			if len(report) != 1 || !strings.HasSuffix(report[0].Name, "blockingBean") {
+				t.Fatalf("expect blocking bean in shutdown report, got %v", report)
+			}
+			if !quick.destroyed {
+				t.Fatal("expect other beans destroyed")
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package appcontext
+
+import (
+	"errors"
+	"reflect"
+)
+
+// 声明监听事件类型的监听器，事件处理器按事件类型分发事件，发布时不需要遍历所有监听器
+type TypedEventListener interface {
+	ApplicationEventListener
+
+	// 监听的事件类型，为interface时接收所有实现该interface的事件
+	EventType() reflect.Type
+}
+
+// 声明监听payload类型的监听器，事件处理器按PayloadApplicationEvent的payload类型分发事件
+type TypedPayloadListener interface {
+	ApplicationEventListener
+
+	// 监听的payload类型，为interface时接收所有实现该interface的payload
+	PayloadType() reflect.Type
+}
+
+type typedListener[E ApplicationEvent] struct {
+	f func(E)
+}
+
+type typedPayloadListener[T any] struct {
+	f func(T)
+}
+
+// 创建监听E类型事件的监听器，通过AddListeners注册
+func NewTypedListener[E ApplicationEvent](f func(E)) TypedEventListener {
+	return &typedListener[E]{f: f}
+}
+
+// 创建监听T类型payload的监听器，通过AddListeners注册或直接注册为bean
+func OnPayload[T any](f func(T)) TypedPayloadListener {
+	return &typedPayloadListener[T]{f: f}
+}
+
+// 注册监听E类型事件的方法，如：
+//  appcontext.Subscribe(app, func(e *appcontext.ContextStartedEvent) {})
+func Subscribe[E ApplicationEvent](handler ApplicationEventHandler, f func(E)) {
+	handler.AddListeners(NewTypedListener(f))
+}
+
+// 发布payload为T类型的PayloadApplicationEvent，由OnPayload创建的监听器及PayloadEventListener接收
+func PublishPayload[T any](pub ApplicationEventPublisher, payload T) error {
+	e := NewPayloadApplicationEvent(payload)
+	if e == nil {
+		return errors.New("payload is nil. ")
+	}
+	return pub.PublishEvent(e)
+}
+
+func (l *typedListener[E]) OnApplicationEvent(e ApplicationEvent) {
+	if v, ok := e.(E); ok {
+		l.f(v)
+	}
+}
+
+func (l *typedListener[E]) EventType() reflect.Type {
+	return reflect.TypeOf((*E)(nil)).Elem()
+}
+
+func (l *typedPayloadListener[T]) OnApplicationEvent(e ApplicationEvent) {
+	if pe, ok := e.(*PayloadApplicationEvent); ok {
+		if v, ok := pe.payload.(T); ok {
+			l.f(v)
+		}
+	}
+}
+
+func (l *typedPayloadListener[T]) PayloadType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// 事件分发表：声明了具体事件类型（或payload类型）的监听器按类型索引，其他监听器对所有事件生效。
+// 通过方法注册的监听器（如func(*ContextStartedEvent)）同样按方法参数的事件类型索引。
+// 同一事件的监听器按注册顺序调用
+type listenerTable struct {
+	seq      int
+	common   []listenerEntry
+	events   map[reflect.Type][]listenerEntry
+	payloads map[reflect.Type][]listenerEntry
+}
+
+type listenerEntry struct {
+	seq      int
+	listener ApplicationEventListener
+	// 异步分发器，为nil时同步调用
+	async *asyncDeliverer
+}
+
+func (t *listenerTable) add(l ApplicationEventListener, async *asyncDeliverer) {
+	t.seq++
+	entry := listenerEntry{seq: t.seq, listener: l, async: async}
+	if tl, ok := l.(TypedEventListener); ok {
+		if et := tl.EventType(); et != nil && et.Kind() != reflect.Interface {
+			if t.events == nil {
+				t.events = map[reflect.Type][]listenerEntry{}
+			}
+			t.events[et] = append(t.events[et], entry)
+			return
+		}
+	}
+	if pl, ok := l.(TypedPayloadListener); ok {
+		if pt := pl.PayloadType(); pt != nil && pt.Kind() != reflect.Interface {
+			if t.payloads == nil {
+				t.payloads = map[reflect.Type][]listenerEntry{}
+			}
+			t.payloads[pt] = append(t.payloads[pt], entry)
+			return
+		}
+	}
+	t.common = append(t.common, entry)
+}
+
+// 获得接收事件e的监听器，按注册顺序排列
+func (t *listenerTable) match(e ApplicationEvent) []listenerEntry {
+	ret := mergeEntries(t.common, t.events[reflect.TypeOf(e)])
+	if pe, ok := e.(*PayloadApplicationEvent); ok && len(t.payloads) > 0 {
+		ret = mergeEntries(ret, t.payloads[reflect.TypeOf(pe.payload)])
+	}
+	return ret
+}
+
+func mergeEntries(a, b []listenerEntry) []listenerEntry {
+	if len(b) == 0 {
+		return a
+	}
+	if len(a) == 0 {
+		return b
+	}
+	ret := make([]listenerEntry, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i].seq < b[j].seq {
+			ret = append(ret, a[i])
+			i++
+		} else {
+			ret = append(ret, b[j])
+			j++
+		}
+	}
+	ret = append(ret, a[i:]...)
+	return append(ret, b[j:]...)
+}
@@ -0,0 +1,88 @@
+package appcontext
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type namedListener struct {
+	name string
+	log  *[]string
+}
+
+func (l *namedListener) OnApplicationEvent(e ApplicationEvent) {
+	*l.log = append(*l.log, l.name)
+}
+
+func TestFuncListenerIndexed(t *testing.T) {
+	p := NewEventProcessor()
+	p.AddListeners(func(e *ContextStartedEvent) {})
+	p.AddListeners(func(e ApplicationEvent) {})
+
+	st := reflect.TypeOf(&ContextStartedEvent{})
+	if len(p.listeners.events[st]) != 1 {
+		t.Fatalf("expect func listener indexed by event type, got %v", p.listeners.events)
+	}
+	// 参数为interface的方法对所有事件生效
+	if len(p.listeners.common) != 1 {
+		t.Fatalf("expect interface func listener in common, got %d", len(p.listeners.common))
+	}
+}
+
+func TestTypedDispatchOrder(t *testing.T) {
+	var log []string
+	record := func(name string) {
+		log = append(log, name)
+	}
+	p := NewEventProcessor()
+	p.AddListeners(&namedListener{name: "common1", log: &log})
+	p.AddListeners(func(e *ContextStartedEvent) { record("func started") })
+	p.AddListeners(NewTypedListener(func(e *ContextStoppedEvent) { record("typed stopped") }))
+	p.AddListeners(func(e ApplicationEvent) { record("common2") })
+	p.AddListeners(NewTypedListener(func(e *ContextStartedEvent) { record("typed started") }))
+	p.AddListeners(OnPayload(func(s string) { record("payload string") }))
+	p.AddListeners(func(e *ContextStoppedEvent) { record("func stopped") })
+	p.AddListeners(&namedListener{name: "common3", log: &log})
+
+	tests := []struct {
+		name   string
+		event  ApplicationEvent
+		expect string
+	}{
+		{
+			name:   "started",
+			event:  &ContextStartedEvent{},
+			expect: "common1,func started,common2,typed started,common3",
+		},
+		{
+			name:   "stopped",
+			event:  &ContextStoppedEvent{},
+			expect: "common1,typed stopped,common2,func stopped,common3",
+		},
+		{
+			name:   "closed",
+			event:  &ContextClosedEvent{},
+			expect: "common1,common2,common3",
+		},
+		{
+			name:   "payload",
+			event:  NewPayloadApplicationEvent("gopher"),
+			expect: "common1,common2,payload string,common3",
+		},
+		{
+			name:   "other payload",
+			event:  NewPayloadApplicationEvent(1),
+			expect: "common1,common2,common3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log = nil
+			_ = p.NotifyEvent(tt.event)
+			if got := strings.Join(log, ","); got != tt.expect {
+				t.Fatalf("expect %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
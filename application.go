@@ -1,11 +1,14 @@
 package gopher
 
 import (
+	"errors"
 	"github.com/xfali/xlog"
 	"github.com/ydx1011/gopher-core/appcontext"
 	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/config"
 	"github.com/ydx1011/gopher-core/util"
 	"github.com/ydx1011/yfig"
+	"time"
 )
 
 type Application interface {
@@ -32,21 +35,42 @@ type Application interface {
 type RegisterOpt = bean.RegisterOpt
 
 type FileConfigApplication struct {
-	ctx    appcontext.ApplicationContext
-	logger xlog.Logger
+	ctx     appcontext.ApplicationContext
+	logger  xlog.Logger
+	sources *config.PropertySources
 }
 
 type Opt func(*FileConfigApplication)
 
+// 使用配置文件创建Application，激活的profile配置文件（如application-dev.yaml）会覆盖到基础配置之上，
+// 激活的profile由环境变量GOPHER_PROFILES_ACTIVE或配置gopher.profiles.active指定。
 func NewFileConfigApplication(configPath string, opts ...Opt) *FileConfigApplication {
+	return NewFileConfigApplicationWithProfiles(configPath, nil, opts...)
+}
+
+// 使用配置文件及指定激活的profile创建Application，profiles为空时与NewFileConfigApplication一致。
+// 配置文件之上会覆盖环境变量中的属性，规则见config.NewEnvSource
+func NewFileConfigApplicationWithProfiles(configPath string, profiles []string, opts ...Opt) *FileConfigApplication {
 	// Disable fig's log
 	//yfig.SetLog(func(format string, o ...interface{}) {})
-	prop, err := yfig.LoadYamlFile(configPath)
+	return NewApplicationWithSources(config.NewPropertySources(
+		config.NewFileSource(configPath, profiles...),
+		config.NewEnvSource(config.DefaultEnvPrefix),
+	), opts...)
+}
+
+// 使用配置属性来源链创建Application，来源的优先级参考config.PropertySources
+func NewApplicationWithSources(sources *config.PropertySources, opts ...Opt) *FileConfigApplication {
+	prop, err := sources.Load()
 	if err != nil {
-		xlog.Errorln("load config file failed: ", err)
+		xlog.Errorln("load config failed: ", err)
 		return nil
 	}
-	return NewApplication(prop, opts...)
+	ret := NewApplication(prop, opts...)
+	if ret != nil {
+		ret.sources = sources
+	}
+	return ret
 }
 
 func NewApplication(prop yfig.Properties, opts ...Opt) *FileConfigApplication {
@@ -72,6 +96,20 @@ func NewApplication(prop yfig.Properties, opts ...Opt) *FileConfigApplication {
 	return ret
 }
 
+// 创建父Application的子应用，子应用的ApplicationContext可以获取及注入父应用中的bean，ctxOpts为子应用ApplicationContext的其他配置，
+// 如appcontext.OptPropagateEvents()。子应用通常不调用Run，而是通过GetApplicationContext的Start及Close单独启动和关闭
+func OptSetParent(parent *FileConfigApplication, ctxOpts ...appcontext.Opt) Opt {
+	return func(app *FileConfigApplication) {
+		opts := append([]appcontext.Opt{appcontext.OptSetParent(parent.ctx)}, ctxOpts...)
+		app.ctx = appcontext.NewDefaultApplicationContext(opts...)
+	}
+}
+
+// 获得应用的ApplicationContext
+func (app *FileConfigApplication) GetApplicationContext() appcontext.ApplicationContext {
+	return app.ctx
+}
+
 func (app *FileConfigApplication) RegisterBean(o interface{}, opts ...RegisterOpt) error {
 	return app.ctx.RegisterBean(o, opts...)
 }
@@ -84,10 +122,42 @@ func (app *FileConfigApplication) AddListeners(listeners ...interface{}) {
 	app.ctx.AddListeners(listeners...)
 }
 
+// 重新加载配置并刷新ApplicationContext，只支持通过配置文件或配置属性来源链创建的Application
+func (app *FileConfigApplication) Reload() error {
+	if app.sources == nil {
+		return errors.New("Application is not created by property sources, cannot reload. ")
+	}
+	prop, err := app.sources.Load()
+	if err != nil {
+		return err
+	}
+	return appcontext.Refresh(app.ctx, prop)
+}
+
+// 启动应用容器，收到SIGHUP或配置文件变化（配置gopher.config.watch.interval）时重新加载配置
 func (app *FileConfigApplication) Run() error {
 	err := app.ctx.Start()
 	if err != nil {
 		return err
 	}
-	return util.HandlerSignal(app.logger, app.ctx.Close)
+	reload := func() {
+		if err := app.Reload(); err != nil {
+			app.logger.Errorln("reload config failed: ", err)
+		}
+	}
+	closers := []func() error{app.ctx.Close}
+	if app.sources != nil {
+		interval := ""
+		if prop := appcontext.GetProperties(app.ctx); prop != nil {
+			interval = prop.Get(config.KeyConfigWatchInterval, "")
+		}
+		if d, err := time.ParseDuration(interval); err == nil && d > 0 {
+			w := config.NewFileWatcher(d, app.sources.Files)
+			w.Start(reload)
+			closers = append([]func() error{w.Stop}, closers...)
+		} else if interval != "" && interval != "0" {
+			app.logger.Warnf("%s must be duration, but get %s\n", config.KeyConfigWatchInterval, interval)
+		}
+	}
+	return util.HandlerSignalWithReload(app.logger, reload, closers...)
 }
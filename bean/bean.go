@@ -1,5 +1,7 @@
 package bean
 
+import "context"
+
 type Initializing interface {
 	// 当初始化和注入完成时回调
 	BeanAfterSet() error
@@ -9,3 +11,15 @@ type Disposable interface {
 	// 进入销毁阶段，应该尽快做回收处理并退出处理任务
 	BeanDestroy() error
 }
+
+// 支持context的Initializing，ctx在超过初始化超时时间（bean.SetInitTimeout）后取消
+type InitializingContext interface {
+	// 当初始化和注入完成时回调
+	BeanAfterSet(ctx context.Context) error
+}
+
+// 支持context的Disposable，ctx在超过销毁超时时间（bean.SetDestroyTimeout或gopher.application.shutdownTimeout）后取消
+type DisposableContext interface {
+	// 进入销毁阶段，应该在ctx取消前完成回收处理并退出处理任务
+	BeanDestroy(ctx context.Context) error
+}
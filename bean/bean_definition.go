@@ -1,6 +1,7 @@
 package bean
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
@@ -39,11 +40,77 @@ type Definition interface {
 	Classify(classifier Classifier) (bool, error)
 }
 
+// 支持替换注入对象的对象定义，用于BeanPostProcessor返回的代理或装饰对象。
+// 设置后Value返回替换的对象（即注入到其他bean中的对象），Interface、AfterSet、Destroy及Classify仍作用于原对象。
+type ProxyDefinition interface {
+	// 设置替换原对象的对象，o为nil时恢复为原对象
+	SetProxy(o interface{}) error
+}
+
+// 支持context的对象定义，初始化及销毁时将ctx传递给实现InitializingContext、DisposableContext的对象
+type ContextDefinition interface {
+	// 与AfterSet一致，ctx用于控制初始化超时
+	AfterSetContext(ctx context.Context) error
+
+	// 与Destroy一致，ctx用于控制销毁超时
+	DestroyContext(ctx context.Context) error
+}
+
+// 初始化对象定义，对象定义实现ContextDefinition时传入ctx
+func AfterSetWithContext(ctx context.Context, d Definition) error {
+	if cd, ok := d.(ContextDefinition); ok {
+		return cd.AfterSetContext(ctx)
+	}
+	return d.AfterSet()
+}
+
+// 销毁对象定义，对象定义实现ContextDefinition时传入ctx
+func DestroyWithContext(ctx context.Context, d Definition) error {
+	if cd, ok := d.(ContextDefinition); ok {
+		return cd.DestroyContext(ctx)
+	}
+	return d.Destroy()
+}
+
+// 对象定义的来源类型
+const (
+	KindObject        = "object"
+	KindFunction      = "function"
+	KindCustomFactory = "customFactory"
+	KindSlice         = "slice"
+	KindMap           = "map"
+)
+
+// 获得对象定义的来源类型，延迟初始化的对象定义返回被包装的对象定义的类型，
+// 通过RegisterBeanDefinitionCreator扩展的对象定义返回其类型名称
+func DefinitionKind(d Definition) string {
+	if ld, ok := d.(LazyDefinition); ok {
+		d = ld.Unwrap()
+	}
+	switch d.(type) {
+	case *objectDefinition:
+		return KindObject
+	case *customMethodBeanDefinition:
+		return KindCustomFactory
+	case *functionExDefinition:
+		return KindFunction
+	case *sliceDefinition:
+		return KindSlice
+	case *mapDefinition:
+		return KindMap
+	}
+	return reflect.TypeOf(d).String()
+}
+
 type DefinitionCreator func(o interface{}) (Definition, error)
 
 var (
-	InitializingType       = reflect.TypeOf((*Initializing)(nil)).Elem()
-	DisposableType         = reflect.TypeOf((*Disposable)(nil)).Elem()
+	InitializingType = reflect.TypeOf((*Initializing)(nil)).Elem()
+	DisposableType   = reflect.TypeOf((*Disposable)(nil)).Elem()
+
+	InitializingContextType = reflect.TypeOf((*InitializingContext)(nil)).Elem()
+	DisposableContextType   = reflect.TypeOf((*DisposableContext)(nil)).Elem()
+
 	ErrorType              = reflect.TypeOf((*error)(nil)).Elem()
 	beanDefinitionCreators = map[reflect.Kind]DefinitionCreator{
 		reflect.Ptr:   newObjectDefinition,
@@ -65,6 +132,9 @@ func CreateBeanDefinition(o interface{}) (Definition, error) {
 	if v, ok := o.(CustomBeanFactory); ok {
 		return newCustomMethodBeanDefinition(v)
 	}
+	if v, ok := o.(InjectableFunction); ok {
+		return newFunctionExDefinition(v)
+	}
 
 	t := reflect.TypeOf(o)
 	creator, ok := beanDefinitionCreators[t.Kind()]
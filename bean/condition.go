@@ -0,0 +1,148 @@
+package bean
+
+import (
+	"fmt"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+	"strings"
+)
+
+// 判断条件时使用的配置属性，由ApplicationContext提供
+type PropertyResolver interface {
+	// 获得配置属性key的值，不存在时返回defaultValue
+	Get(key string, defaultValue string) string
+
+	// 获得激活的profile
+	ActiveProfiles() []string
+}
+
+// bean注册条件，在ApplicationContext启动注入之前进行判断，不满足条件的bean不会被注册到容器中
+type Condition interface {
+	// 判断是否满足条件
+	// props：配置属性，container：bean容器，self：待判断bean的对象定义
+	// return：ok：满足条件返回true，否则返回false；reason：判断结果的说明
+	Matches(props PropertyResolver, container Container, self Definition) (ok bool, reason string)
+}
+
+// 方法形式的Condition
+type ConditionFunc func(props PropertyResolver, container Container, self Definition) (bool, string)
+
+func (f ConditionFunc) Matches(props PropertyResolver, container Container, self Definition) (bool, string) {
+	return f(props, container, self)
+}
+
+// 配置bean的注册条件，所有条件都满足时才会注册
+func OnCondition(conditions ...Condition) RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetConditions, conditions)
+	}
+}
+
+// 配置属性key的值为value时注册，value为空时只要属性存在且不为false即注册
+func OnProperty(key, value string) RegisterOpt {
+	return OnCondition(ConditionFunc(func(props PropertyResolver, container Container, self Definition) (bool, string) {
+		v := props.Get(key, "")
+		if value == "" {
+			if v == "" || v == "false" {
+				return false, fmt.Sprintf("property [%s] not found or false", key)
+			}
+			return true, fmt.Sprintf("property [%s] found", key)
+		}
+		if v != value {
+			return false, fmt.Sprintf("property [%s] is [%s], expect [%s]", key, v, value)
+		}
+		return true, fmt.Sprintf("property [%s] is [%s]", key, v)
+	}))
+}
+
+// 容器中不存在类型为t的bean时注册
+// t可以为reflect.Type或者该类型的空指针，interface使用其指针，如：bean.OnMissingBean((*io.Writer)(nil))
+func OnMissingBean(t interface{}) RegisterOpt {
+	bt := conditionType(t)
+	return OnCondition(ConditionFunc(func(props PropertyResolver, container Container, self Definition) (bool, string) {
+		if name, ok := findBeanByType(container, bt, self); ok {
+			return false, fmt.Sprintf("found bean [%s] of type [%s]", name, reflection.GetTypeName(bt))
+		}
+		return true, fmt.Sprintf("no bean of type [%s]", reflection.GetTypeName(bt))
+	}))
+}
+
+// 容器中存在类型为t的bean时注册，t的规则与OnMissingBean一致
+func OnBean(t interface{}) RegisterOpt {
+	bt := conditionType(t)
+	return OnCondition(ConditionFunc(func(props PropertyResolver, container Container, self Definition) (bool, string) {
+		if name, ok := findBeanByType(container, bt, self); ok {
+			return true, fmt.Sprintf("found bean [%s] of type [%s]", name, reflection.GetTypeName(bt))
+		}
+		return false, fmt.Sprintf("no bean of type [%s]", reflection.GetTypeName(bt))
+	}))
+}
+
+// 配置的profile中任意一个处于激活状态时注册，profile以"!"开头表示该profile未激活时注册，如：
+// bean.OnProfile("dev", "test")、bean.OnProfile("!prod")
+func OnProfile(profiles ...string) RegisterOpt {
+	return OnCondition(ConditionFunc(func(props PropertyResolver, container Container, self Definition) (bool, string) {
+		active := props.ActiveProfiles()
+		for _, p := range profiles {
+			if strings.HasPrefix(p, "!") {
+				if !contains(active, p[1:]) {
+					return true, fmt.Sprintf("profile [%s] not active", p[1:])
+				}
+			} else if contains(active, p) {
+				return true, fmt.Sprintf("profile [%s] active", p)
+			}
+		}
+		return false, fmt.Sprintf("active profiles [%s] not match [%s]", strings.Join(active, ","), strings.Join(profiles, ","))
+	}))
+}
+
+func contains(s []string, v string) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// 获得注册配置中的所有条件
+func GetConditions(opts ...RegisterOpt) []Condition {
+	c := &conditionCollector{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c.conditions
+}
+
+type conditionCollector struct {
+	conditions []Condition
+}
+
+func (c *conditionCollector) Set(key string, value interface{}) {
+	if key == KeySetConditions {
+		c.conditions = append(c.conditions, value.([]Condition)...)
+	}
+}
+
+func conditionType(t interface{}) reflect.Type {
+	if rt, ok := t.(reflect.Type); ok {
+		return rt
+	}
+	rt := reflect.TypeOf(t)
+	if rt.Kind() == reflect.Ptr && rt.Elem().Kind() == reflect.Interface {
+		return rt.Elem()
+	}
+	return rt
+}
+
+func findBeanByType(container Container, t reflect.Type, self Definition) (string, bool) {
+	found := ""
+	ScanHierarchy(container, func(key string, value Definition) bool {
+		if value != self && value.Type().AssignableTo(t) {
+			found = key
+			return false
+		}
+		return true
+	})
+	return found, found != ""
+}
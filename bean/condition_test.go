@@ -0,0 +1,127 @@
+package bean
+
+import (
+	"io"
+	"testing"
+)
+
+type testProperties struct {
+	values   map[string]string
+	profiles []string
+}
+
+func (p *testProperties) Get(key string, defaultValue string) string {
+	if v, ok := p.values[key]; ok {
+		return v
+	}
+	return defaultValue
+}
+
+func (p *testProperties) ActiveProfiles() []string {
+	return p.profiles
+}
+
+type conditionWriter struct{}
+
+func (w *conditionWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+type conditionReader struct{}
+
+func matches(t *testing.T, opt RegisterOpt, props PropertyResolver, container Container, self Definition) bool {
+	conditions := GetConditions(opt)
+	if len(conditions) != 1 {
+		t.Fatalf("expect one condition, got %d", len(conditions))
+	}
+	ok, reason := conditions[0].Matches(props, container, self)
+	if reason == "" {
+		t.Fatal("expect reason")
+	}
+	return ok
+}
+
+func TestOnProperty(t *testing.T) {
+	props := &testProperties{values: map[string]string{
+		"cache.enabled": "true",
+		"cache.type":    "redis",
+		"mq.enabled":    "false",
+	}}
+	tests := []struct {
+		name   string
+		key    string
+		value  string
+		expect bool
+	}{
+		{name: "value match", key: "cache.type", value: "redis", expect: true},
+		{name: "value mismatch", key: "cache.type", value: "memory", expect: false},
+		{name: "exists", key: "cache.enabled", expect: true},
+		{name: "false", key: "mq.enabled", expect: false},
+		{name: "missing", key: "db.enabled", expect: false},
+		{name: "missing with value", key: "db.type", value: "mysql", expect: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matches(t, OnProperty(tt.key, tt.value), props, NewContainer(), nil); got != tt.expect {
+				t.Fatalf("expect %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestOnBean(t *testing.T) {
+	c := NewContainer()
+	_ = c.Register(&conditionWriter{})
+	self, _ := CreateBeanDefinition(&conditionWriter{})
+	registered, _ := c.GetDefinition(self.Name())
+
+	tests := []struct {
+		name    string
+		t       interface{}
+		self    Definition
+		missing bool
+		expect  bool
+	}{
+		{name: "type found", t: &conditionWriter{}, expect: true},
+		{name: "interface found", t: (*io.Writer)(nil), expect: true},
+		{name: "not found", t: &conditionReader{}, expect: false},
+		{name: "missing type found", t: &conditionWriter{}, missing: true, expect: false},
+		{name: "missing interface not found", t: (*io.Reader)(nil), missing: true, expect: true},
+		// 判断时忽略bean自身
+		{name: "self ignored", t: (*io.Writer)(nil), self: registered, missing: true, expect: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := OnBean(tt.t)
+			if tt.missing {
+				opt = OnMissingBean(tt.t)
+			}
+			if got := matches(t, opt, &testProperties{}, c, tt.self); got != tt.expect {
+				t.Fatalf("expect %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestOnProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		active   []string
+		profiles []string
+		expect   bool
+	}{
+		{name: "active", active: []string{"dev"}, profiles: []string{"dev", "test"}, expect: true},
+		{name: "not active", active: []string{"prod"}, profiles: []string{"dev", "test"}, expect: false},
+		{name: "none active", profiles: []string{"dev"}, expect: false},
+		{name: "negated", active: []string{"dev"}, profiles: []string{"!prod"}, expect: true},
+		{name: "negated active", active: []string{"prod"}, profiles: []string{"!prod"}, expect: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props := &testProperties{profiles: tt.active}
+			if got := matches(t, OnProfile(tt.profiles...), props, NewContainer(), nil); got != tt.expect {
+				t.Fatalf("expect %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
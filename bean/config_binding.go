@@ -0,0 +1,30 @@
+package bean
+
+import (
+	"github.com/ydx1011/gopher-core/config"
+	"github.com/ydx1011/yfig"
+)
+
+// 配置属性对象
+type ConfigBinding struct {
+	// 绑定的属性前缀
+	Prefix string
+
+	// 绑定的对象，必须为struct指针
+	Target interface{}
+}
+
+// 创建配置属性对象，通过ApplicationContext注册后（名称为o的类型名称），在注入之前使用配置中prefix下的属性填充o并校验，
+// 之后o可以像普通bean一样被注入到其他bean中。绑定及校验规则见config.Bind，如：
+// app.RegisterBean(bean.BindConfig("datasource", &DataSourceConfig{}))
+func BindConfig(prefix string, o interface{}) *ConfigBinding {
+	return &ConfigBinding{
+		Prefix: prefix,
+		Target: o,
+	}
+}
+
+// 使用配置属性填充并校验Target
+func (b *ConfigBinding) Bind(conf yfig.Properties) error {
+	return config.Bind(conf, b.Prefix, b.Target)
+}
@@ -1,5 +1,7 @@
 package bean
 
+import "fmt"
+
 type Container interface {
 	// 注册对象
 	// opts添加bean注册的配置，详情查看RegisterOpt
@@ -14,6 +16,7 @@ type Container interface {
 	Get(name string) (o interface{}, ok bool)
 
 	// 根据类型获得对象，值设置到参数中
+	// o必须为指向目标类型的指针，如：var x SomeIface; container.GetByType(&x)
 	// return：ok：如果成功为true，否则为false
 	GetByType(o interface{}) bool
 
@@ -30,3 +33,33 @@ type Container interface {
 	// 返回true则继续遍历，返回false停止遍历。
 	Scan(f func(key string, value Definition) bool)
 }
+
+// 提供对象注册配置信息的容器（可选接口）
+type RegisterInfoProvider interface {
+	// 获得对象注册时的配置信息
+	// return：ok：如果成功为true，否则为false
+	GetRegisterInfo(name string) (info RegisterInfo, ok bool)
+}
+
+// 支持自定义作用域的容器（可选接口）
+type ScopeRegistry interface {
+	// 注册自定义作用域，注册后可通过bean.SetScope(scope.Name())使用
+	// return：失败（如名称重复）返回错误
+	RegisterScope(scope Scope) error
+}
+
+// 获得对象注册时的配置信息，容器未实现RegisterInfoProvider时返回false
+func GetRegisterInfo(c Container, name string) (RegisterInfo, bool) {
+	if p, ok := c.(RegisterInfoProvider); ok {
+		return p.GetRegisterInfo(name)
+	}
+	return RegisterInfo{}, false
+}
+
+// 向容器注册自定义作用域，容器未实现ScopeRegistry时返回错误
+func RegisterScope(c Container, scope Scope) error {
+	if r, ok := c.(ScopeRegistry); ok {
+		return r.RegisterScope(scope)
+	}
+	return fmt.Errorf("Container %T not support custom scope. ", c)
+}
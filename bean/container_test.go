@@ -0,0 +1,77 @@
+package bean
+
+import (
+	"testing"
+)
+
+// 只实现Container接口的容器
+type minimalContainer struct {
+	c Container
+}
+
+func (m *minimalContainer) Register(o interface{}, opts ...RegisterOpt) error {
+	return m.c.Register(o, opts...)
+}
+
+func (m *minimalContainer) RegisterByName(name string, o interface{}, opts ...RegisterOpt) error {
+	return m.c.RegisterByName(name, o, opts...)
+}
+
+func (m *minimalContainer) Get(name string) (interface{}, bool) {
+	return m.c.Get(name)
+}
+
+func (m *minimalContainer) GetByType(o interface{}) bool {
+	return m.c.GetByType(o)
+}
+
+func (m *minimalContainer) GetDefinition(name string) (Definition, bool) {
+	return m.c.GetDefinition(name)
+}
+
+func (m *minimalContainer) PutDefinition(name string, definition Definition) error {
+	return m.c.PutDefinition(name, definition)
+}
+
+func (m *minimalContainer) Scan(f func(key string, value Definition) bool) {
+	m.c.Scan(f)
+}
+
+type testScope struct {
+	*prototypeScope
+}
+
+func (s testScope) Name() string {
+	return "test"
+}
+
+func TestOptionalContainerInterfaces(t *testing.T) {
+	tests := []struct {
+		name      string
+		container Container
+		info      bool
+		scope     bool
+	}{
+		{name: "default", container: NewContainer(), info: true, scope: true},
+		{name: "minimal", container: &minimalContainer{c: NewContainer()}, info: false, scope: false},
+		{name: "child of minimal", container: NewContainer(OptSetParent(&minimalContainer{c: NewContainer()})), info: true, scope: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.container.RegisterByName("a", &scopeTestBean{}, SetPrimary()); err != nil {
+				t.Fatal(err)
+			}
+			info, ok := GetRegisterInfo(tt.container, "a")
+			if ok != tt.info || (ok && !info.Primary) {
+				t.Fatalf("expect register info %v, got %v %v", tt.info, info, ok)
+			}
+			if _, ok := GetRegisterInfo(tt.container, "missing"); ok {
+				t.Fatal("expect no register info for missing bean")
+			}
+			err := RegisterScope(tt.container, testScope{NewPrototypeScope()})
+			if (err == nil) != tt.scope {
+				t.Fatalf("expect register scope %v, got %v", tt.scope, err)
+			}
+		})
+	}
+}
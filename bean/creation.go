@@ -0,0 +1,92 @@
+package bean
+
+import (
+	"reflect"
+)
+
+// 创建链，记录同一调用链上正在创建或初始化的对象定义，用于检测重入（循环依赖）。
+// 创建链通过容器传递：WithCreation返回携带创建链的容器，注入时通过ValueOf从该容器获取对象定义的值，
+// 对象定义创建对象时再将自身加入创建链传递给参数的注入。
+type Creation struct {
+	def    interface{}
+	parent *Creation
+}
+
+// 返回在当前创建链上加入对象定义d的新创建链，原创建链不变
+func (c *Creation) With(d interface{}) *Creation {
+	return &Creation{
+		def:    d,
+		parent: c,
+	}
+}
+
+// 对象定义d是否在创建链上
+func (c *Creation) Contains(d interface{}) bool {
+	for x := c; x != nil; x = x.parent {
+		if x.def == d {
+			return true
+		}
+	}
+	return false
+}
+
+// 携带创建链的容器
+type creationContainer struct {
+	Container
+	creation *Creation
+}
+
+// 返回携带创建链creation的容器，creation为nil时返回不携带创建链的原容器
+func WithCreation(c Container, creation *Creation) Container {
+	if cc, ok := c.(*creationContainer); ok {
+		c = cc.Container
+	}
+	if creation == nil {
+		return c
+	}
+	return &creationContainer{
+		Container: c,
+		creation:  creation,
+	}
+}
+
+// 获得容器携带的创建链，未携带时返回nil
+func CreationOf(c Container) *Creation {
+	if cc, ok := c.(*creationContainer); ok {
+		return cc.creation
+	}
+	return nil
+}
+
+func (c *creationContainer) GetRegisterInfo(name string) (RegisterInfo, bool) {
+	return GetRegisterInfo(c.Container, name)
+}
+
+func (c *creationContainer) RegisterScope(scope Scope) error {
+	return RegisterScope(c.Container, scope)
+}
+
+func (c *creationContainer) Parent() Container {
+	if hc, ok := c.Container.(HierarchicalContainer); ok {
+		return hc.Parent()
+	}
+	return nil
+}
+
+// 支持在创建链中获取值的对象定义
+type creationValuer interface {
+	valueIn(creation *Creation) reflect.Value
+}
+
+// 从容器c获得对象定义d的值，c携带创建链时对象定义在该创建链中创建（或初始化）对象。
+// 注入时应使用该方法代替Definition.Value
+func ValueOf(c Container, d Definition) reflect.Value {
+	return valueIn(d, CreationOf(c))
+}
+
+func valueIn(d Definition, creation *Creation) reflect.Value {
+	if v, ok := d.(creationValuer); ok {
+		return v.valueIn(creation)
+	}
+	return d.Value()
+}
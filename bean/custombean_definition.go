@@ -9,7 +9,8 @@ import (
 type CustomBeanFactory interface {
 	// 返回或者创建bean的方法
 	// 该方法可能包含一个或者多个参数，参数会在实例化时自动注入
-	// 该方法只能有一个返回值，返回的值将被注入到依赖该类型值的对象中
+	// 该方法的返回值可以为T、(T, error)或(T, func(), error)，返回的T将被注入到依赖该类型值的对象中，
+	// 返回的错误会使bean创建失败，返回的清理方法在容器关闭时调用
 	BeanFactory() interface{}
 
 	// BeanFactory返回创建bean方法如果带参数，且参数需要指定注入名称时将根据InjectNames返回的名称列表进行匹配
@@ -34,8 +35,8 @@ type defaultCustomBeanFactory struct {
 }
 
 func NewCustomBeanFactory(beanFunc interface{}, initMethod, destroyMethod string) *defaultCustomBeanFactory {
-	ft := reflect.TypeOf(beanFunc)
-	if err := verifyBeanFunctionEx(ft); err != nil {
+	ft := functionType(beanFunc)
+	if err := VerifyBeanFunction(ft); err != nil {
 		panic(fmt.Errorf("NewCustomMethodBean with a invalid function type: %s, error: %v", ft.String(), err))
 	}
 	return &defaultCustomBeanFactory{
@@ -46,8 +47,8 @@ func NewCustomBeanFactory(beanFunc interface{}, initMethod, destroyMethod string
 }
 
 func NewCustomBeanFactoryWithName(beanFunc interface{}, names []string, initMethod, destroyMethod string) *defaultCustomBeanFactory {
-	ft := reflect.TypeOf(beanFunc)
-	if err := verifyBeanFunctionEx(ft); err != nil {
+	ft := functionType(beanFunc)
+	if err := VerifyBeanFunction(ft); err != nil {
 		panic(fmt.Errorf("NewCustomMethodBean with a invalid function type: %s", ft.String()))
 	}
 	return &defaultCustomBeanFactory{
@@ -58,6 +59,14 @@ func NewCustomBeanFactoryWithName(beanFunc interface{}, names []string, initMeth
 	}
 }
 
+// 获得创建bean方法的类型，InjectableFunction返回其无参数创建方法的类型
+func functionType(beanFunc interface{}) reflect.Type {
+	if f, ok := beanFunc.(InjectableFunction); ok {
+		beanFunc = f.Function()
+	}
+	return reflect.TypeOf(beanFunc)
+}
+
 func (b *defaultCustomBeanFactory) BeanFactory() interface{} {
 	return b.beanFunc
 }
@@ -75,7 +84,7 @@ func (b *defaultCustomBeanFactory) DestroyMethodName() string {
 }
 
 type customMethodBeanDefinition struct {
-	functionExDefinition
+	*functionExDefinition
 	initializingFuncName string
 	disposableFuncName   string
 }
@@ -86,7 +95,7 @@ func newCustomMethodBeanDefinition(b CustomBeanFactory) (Definition, error) {
 		return nil, err
 	}
 	ret := &customMethodBeanDefinition{
-		functionExDefinition: *d.(*functionExDefinition),
+		functionExDefinition: d.(*functionExDefinition),
 		initializingFuncName: b.InitMethodName(),
 		disposableFuncName:   b.DestroyMethodName(),
 	}
@@ -2,10 +2,12 @@ package bean
 
 import (
 	"errors"
+	"fmt"
 	"github.com/ydx1011/gopher-core/reflection"
 	"github.com/ydx1011/gopher-core/util/skiplist"
 	"reflect"
 	"sync"
+	"time"
 )
 
 const (
@@ -17,6 +19,11 @@ const (
 type defaultContainer struct {
 	enableCache bool
 	objectPool  *pool
+
+	scopes    map[string]Scope
+	scopeLock sync.RWMutex
+
+	parent Container
 }
 
 type ContainerOpt func(*defaultContainer)
@@ -24,6 +31,11 @@ type ContainerOpt func(*defaultContainer)
 func NewContainer(opts ...ContainerOpt) *defaultContainer {
 	ret := &defaultContainer{
 		enableCache: defaultEnableCache,
+		scopes: map[string]Scope{
+			ScopeSingleton:        NewSingletonScope(),
+			ScopePrototype:        NewPrototypeScope(),
+			ScopeTrackedPrototype: NewTrackedPrototypeScope(),
+		},
 	}
 	for _, opt := range opts {
 		opt(ret)
@@ -56,6 +68,13 @@ func (c *defaultContainer) RegisterByName(name string, o interface{}, opts ...Re
 
 	elem := newElem(opts...)
 	elem.def = beanDefinition
+	err = c.applyScope(name, elem)
+	if err != nil {
+		return err
+	}
+	if elem.lazy {
+		elem.def = newLazyDefinition(elem.def)
+	}
 	_, loaded := c.objectPool.loadOrStore(name, elem)
 	if loaded {
 		return errors.New(name + " bean is exists. ")
@@ -81,9 +100,37 @@ func (c *defaultContainer) GetDefinition(name string) (Definition, bool) {
 	if load {
 		return o.def, load
 	}
+	if c.parent != nil {
+		return c.parent.GetDefinition(name)
+	}
 	return nil, false
 }
 
+func (c *defaultContainer) GetRegisterInfo(name string) (RegisterInfo, bool) {
+	o, load := c.objectPool.load(name)
+	if load {
+		return RegisterInfo{
+			Order:       o.order,
+			Scope:       o.scope,
+			Primary:     o.primary,
+			Qualifiers:  o.qualifiers,
+			Refreshable: o.refreshable,
+			Lazy:        o.lazy,
+
+			InitTimeout:    o.initTimeout,
+			DestroyTimeout: o.destroyTimeout,
+		}, true
+	}
+	if c.parent != nil {
+		return GetRegisterInfo(c.parent, name)
+	}
+	return RegisterInfo{}, false
+}
+
+func (c *defaultContainer) Parent() Container {
+	return c.parent
+}
+
 func (c *defaultContainer) Get(name string) (interface{}, bool) {
 	o, load := c.GetDefinition(name)
 	if load {
@@ -94,13 +141,52 @@ func (c *defaultContainer) Get(name string) (interface{}, bool) {
 
 func (c *defaultContainer) GetByType(o interface{}) bool {
 	v := reflect.ValueOf(o)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false
+	}
+	v = v.Elem()
 	d, ok := c.GetDefinition(reflection.GetTypeName(v.Type()))
 	if ok {
-		v.Set(d.Value())
+		ov := d.Value()
+		if ov.Type().AssignableTo(v.Type()) {
+			v.Set(ov)
+			return true
+		}
 	}
 	return false
 }
 
+func (c *defaultContainer) RegisterScope(scope Scope) error {
+	if scope == nil {
+		return errors.New("Scope is nil. ")
+	}
+	c.scopeLock.Lock()
+	defer c.scopeLock.Unlock()
+
+	if _, ok := c.scopes[scope.Name()]; ok {
+		return errors.New(scope.Name() + " scope is exists. ")
+	}
+	c.scopes[scope.Name()] = scope
+	return nil
+}
+
+func (c *defaultContainer) applyScope(name string, e *elem) error {
+	c.scopeLock.RLock()
+	scope, ok := c.scopes[e.scope]
+	c.scopeLock.RUnlock()
+	if !ok {
+		return errors.New(e.scope + " scope not found. ")
+	}
+
+	if d, ok := e.def.(ScopedDefinition); ok {
+		return d.SetScope(name, scope)
+	}
+	if e.scope != ScopeSingleton {
+		return fmt.Errorf("Bean %s with scope %s: only bean function support scope. ", name, e.scope)
+	}
+	return nil
+}
+
 func (c *defaultContainer) Scan(f func(key string, value Definition) bool) {
 	keys := c.objectPool.keys()
 	for _, k := range keys {
@@ -193,13 +279,23 @@ func (p *pool) load(name string) (*elem, bool) {
 }
 
 type elem struct {
-	def   Definition
-	order int
+	def        Definition
+	order      int
+	scope      string
+	primary    bool
+	qualifiers []string
+
+	refreshable bool
+	lazy        bool
+
+	initTimeout    time.Duration
+	destroyTimeout time.Duration
 }
 
 func newElem(opts ...RegisterOpt) *elem {
 	ret := &elem{
 		order: defaultOrder,
+		scope: ScopeSingleton,
 	}
 	for _, opt := range opts {
 		opt(ret)
@@ -208,7 +304,22 @@ func newElem(opts ...RegisterOpt) *elem {
 }
 
 func (e *elem) Set(key string, value interface{}) {
-	if key == KeySetOrder {
+	switch key {
+	case KeySetOrder:
 		e.order = value.(int)
+	case KeySetScope:
+		e.scope = value.(string)
+	case KeySetPrimary:
+		e.primary = value.(bool)
+	case KeySetQualifiers:
+		e.qualifiers = append(e.qualifiers, value.([]string)...)
+	case KeySetRefreshable:
+		e.refreshable = value.(bool)
+	case KeySetLazy:
+		e.lazy = value.(bool)
+	case KeySetInitTimeout:
+		e.initTimeout = value.(time.Duration)
+	case KeySetDestroyTimeout:
+		e.destroyTimeout = value.(time.Duration)
 	}
 }
@@ -1,6 +1,7 @@
 package bean
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	errors2 "github.com/ydx1011/gopher-core/errors"
@@ -10,35 +11,78 @@ import (
 	"sync/atomic"
 )
 
-const (
-	functionDefinitionNone = iota
-	functionDefinitionInjecting
-)
-
 var (
 	DummyType  = reflect.TypeOf((*struct{})(nil)).Elem()
 	DummyValue = reflect.ValueOf(struct{}{})
 )
 
 type functionExDefinition struct {
-	name   string
-	o      interface{}
-	fn     reflect.Value
-	t      reflect.Type
-	status int32
+	name    string
+	o       interface{}
+	fn      reflect.Value
+	factory InjectableFunction
+	t       reflect.Type
+
+	beanName    string
+	scope       Scope
+	initOnce    int32
+	destroyOnce int32
+
+	proxy     reflect.Value
+	processor atomic.Value
+
+	cleanups    []func()
+	cleanupLock sync.Mutex
+}
 
-	instances    reflect.Value
-	instanceLock sync.RWMutex
-	initOnce     int32
-	destroyOnce  int32
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	cleanupType = reflect.TypeOf((func())(nil))
+)
+
+// 参数由容器注入的创建bean方法，injector将带参数的创建方法包装为该类型。
+// 对象定义创建对象时传入当前的创建链，参数在该创建链中获取，从而能够检测创建时的循环依赖
+type InjectableFunction interface {
+	// 获得无参数的创建方法，返回值形式与VerifyBeanFunction一致，调用时不携带创建链
+	Function() interface{}
+
+	// 在创建链creation中注入参数并调用创建方法，返回创建方法的返回值
+	Call(creation *Creation) []reflect.Value
 }
 
-func verifyBeanFunctionEx(ft reflect.Type) error {
+// 对象创建后的处理方法，o为新创建的对象，initialize执行对象的初始化（BeanAfterSet），
+// 返回的对象替换新创建的对象
+type InstanceProcessor func(o interface{}, initialize func() error) (interface{}, error)
+
+// 支持处理运行时创建对象的对象定义（可选接口）。
+// 作用域不持有的对象（如多例）以及容器初始化完成后创建的对象不会经过容器启动时的分类及初始化，
+// 设置处理方法后每个新创建的对象都由处理方法完成分类、初始化等处理
+type InstanceProcessable interface {
+	// 设置对象创建后的处理方法
+	SetInstanceProcessor(p InstanceProcessor)
+}
+
+// 检查创建bean的方法，支持的返回值形式为：
+// T、(T, error)、(T, func(), error)，其中T必须为pointer或者interface，func()为清理方法，在容器关闭时调用
+func VerifyBeanFunction(ft reflect.Type) error {
 	if ft.Kind() != reflect.Func {
 		return errors.New("Param not function ")
 	}
-	if ft.NumOut() != 1 {
-		return errors.New("Bean function must ONLY have 1 return value ")
+	switch ft.NumOut() {
+	case 1:
+	case 2:
+		if ft.Out(1) != errorType {
+			return errors.New("Bean function 2nd return value must be error ")
+		}
+	case 3:
+		if ft.Out(1) != cleanupType {
+			return errors.New("Bean function 2nd return value must be func() ")
+		}
+		if ft.Out(2) != errorType {
+			return errors.New("Bean function 3rd return value must be error ")
+		}
+	default:
+		return errors.New("Bean function must return T, (T, error) or (T, func(), error) ")
 	}
 
 	rt := ft.Out(0)
@@ -49,25 +93,77 @@ func verifyBeanFunctionEx(ft reflect.Type) error {
 	return nil
 }
 
+// 解析创建bean方法的返回值，返回创建的对象、清理方法（可能为nil）及错误
+func ParseBeanFunctionResults(results []reflect.Value) (reflect.Value, func(), error) {
+	v := results[0]
+	var cleanup func()
+	var err error
+	switch len(results) {
+	case 2:
+		err, _ = results[1].Interface().(error)
+	case 3:
+		cleanup, _ = results[1].Interface().(func())
+		err, _ = results[2].Interface().(error)
+	}
+	return v, cleanup, err
+}
+
 func newFunctionExDefinition(o interface{}) (Definition, error) {
+	factory, _ := o.(InjectableFunction)
+	if factory != nil {
+		o = factory.Function()
+	}
 	ft := reflect.TypeOf(o)
-	err := verifyBeanFunctionEx(ft)
+	err := VerifyBeanFunction(ft)
 	if err != nil {
 		return nil, err
 	}
 	ot := ft.Out(0)
 	fn := reflect.ValueOf(o)
 	ret := &functionExDefinition{
-		o:         o,
-		name:      reflection.GetTypeName(ot),
-		fn:        fn,
-		t:         ot,
-		instances: reflect.MakeMap(reflect.MapOf(ot, DummyType)),
+		o:       o,
+		name:    reflection.GetTypeName(ot),
+		fn:      fn,
+		factory: factory,
+		t:       ot,
+		scope:   NewSingletonScope(),
 	}
+	ret.beanName = ret.name
 	return ret, nil
 }
 
+func (d *functionExDefinition) SetScope(name string, scope Scope) error {
+	if scope == nil {
+		return errors.New("Scope is nil. ")
+	}
+	if name != "" {
+		d.beanName = name
+	}
+	d.scope = scope
+	return nil
+}
+
+func (d *functionExDefinition) Scope() Scope {
+	return d.scope
+}
+
+// 只有单例支持替换对象
+func (d *functionExDefinition) SetProxy(o interface{}) error {
+	if o == nil {
+		d.proxy = reflect.Value{}
+		return nil
+	}
+	if d.scope.Name() != ScopeSingleton {
+		return fmt.Errorf("Bean %s with scope %s: only singleton support proxy. ", d.beanName, d.scope.Name())
+	}
+	d.proxy = reflect.ValueOf(o)
+	return nil
+}
+
 func (d *functionExDefinition) Type() reflect.Type {
+	if d.proxy.IsValid() {
+		return d.proxy.Type()
+	}
 	return d.t
 }
 
@@ -75,19 +171,73 @@ func (d *functionExDefinition) Name() string {
 	return d.name
 }
 
+func (d *functionExDefinition) SetInstanceProcessor(p InstanceProcessor) {
+	d.processor.Store(p)
+}
+
 func (d *functionExDefinition) Value() reflect.Value {
-	if atomic.CompareAndSwapInt32(&d.status, functionDefinitionNone, functionDefinitionInjecting) {
-		defer atomic.CompareAndSwapInt32(&d.status, functionDefinitionInjecting, functionDefinitionNone)
-		v := d.fn.Call(nil)[0]
-		if v.IsValid() {
-			d.instanceLock.Lock()
-			defer d.instanceLock.Unlock()
-			d.instances.SetMapIndex(v, DummyValue)
-		}
-		return v
-	} else {
+	return d.valueIn(nil)
+}
+
+// 对象定义已在创建链上说明创建对象时再次获取了自身（循环依赖），返回错误而不是等待作用域中正在进行的创建
+func (d *functionExDefinition) valueIn(creation *Creation) reflect.Value {
+	if d.proxy.IsValid() {
+		return d.proxy
+	}
+	if creation.Contains(d) {
 		panic(fmt.Errorf("BeanDefinition: [Function] inject type [%s] Circular dependency ", d.name))
 	}
+	v, err := d.scope.Get(d.beanName, func() (reflect.Value, error) {
+		return d.create(creation.With(d))
+	})
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (d *functionExDefinition) create(creation *Creation) (reflect.Value, error) {
+	var results []reflect.Value
+	if d.factory != nil {
+		results = d.factory.Call(creation)
+	} else {
+		results = d.fn.Call(nil)
+	}
+	v, cleanup, err := ParseBeanFunctionResults(results)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	untracked := isUntracked(d.scope)
+	if cleanup != nil && !untracked {
+		d.cleanupLock.Lock()
+		d.cleanups = append(d.cleanups, cleanup)
+		d.cleanupLock.Unlock()
+	}
+	// 已经过了初始化阶段（如多例在运行时创建）或作用域不持有对象则立即处理
+	if untracked || atomic.LoadInt32(&d.initOnce) == 1 {
+		return d.process(v)
+	}
+	return v, nil
+}
+
+// 处理运行时创建的对象，未设置处理方法时只执行初始化
+func (d *functionExDefinition) process(v reflect.Value) (reflect.Value, error) {
+	initialize := func() error {
+		return initializeValue(context.Background(), v)
+	}
+	p, _ := d.processor.Load().(InstanceProcessor)
+	if p == nil || !v.IsValid() || v.IsNil() {
+		return v, initialize()
+	}
+	o, err := p(v.Interface(), initialize)
+	if err != nil {
+		return v, err
+	}
+	ov := reflect.ValueOf(o)
+	if !ov.IsValid() || !ov.Type().AssignableTo(d.t) {
+		return v, fmt.Errorf("Bean %s processed object %T is not assignable to %s ", d.beanName, o, d.t.String())
+	}
+	return ov, nil
 }
 
 func (d *functionExDefinition) Interface() interface{} {
@@ -99,21 +249,18 @@ func (d *functionExDefinition) IsObject() bool {
 }
 
 func (d *functionExDefinition) AfterSet() error {
+	return d.AfterSetContext(context.Background())
+}
+
+func (d *functionExDefinition) AfterSetContext(ctx context.Context) error {
 	if atomic.CompareAndSwapInt32(&d.initOnce, 0, 1) {
-		d.instanceLock.RLock()
-		defer d.instanceLock.RUnlock()
 		var errs errors2.Errors
-
-		for _, i := range d.instances.MapKeys() {
-			if i.IsValid() && !i.IsNil() {
-				if v, ok := i.Interface().(Initializing); ok {
-					err := v.BeanAfterSet()
-					if err != nil {
-						_ = errs.AddError(err)
-					}
-				}
+		d.scope.Scan(d.beanName, func(o reflect.Value) bool {
+			if err := initializeValue(ctx, o); err != nil {
+				_ = errs.AddError(err)
 			}
-		}
+			return true
+		})
 		if errs.Empty() {
 			return nil
 		}
@@ -123,18 +270,25 @@ func (d *functionExDefinition) AfterSet() error {
 }
 
 func (d *functionExDefinition) Destroy() error {
+	return d.DestroyContext(context.Background())
+}
+
+func (d *functionExDefinition) DestroyContext(ctx context.Context) error {
 	if atomic.CompareAndSwapInt32(&d.destroyOnce, 0, 1) {
-		d.instanceLock.RLock()
-		defer d.instanceLock.RUnlock()
 		var errs errors2.Errors
-		for _, i := range d.instances.MapKeys() {
-			if i.IsValid() && !i.IsNil() {
-				if v, ok := i.Interface().(Disposable); ok {
-					err := v.BeanDestroy()
-					if err != nil {
-						_ = errs.AddError(err)
-					}
-				}
+		for _, o := range d.scope.Remove(d.beanName) {
+			if err := destroyValue(ctx, o); err != nil {
+				_ = errs.AddError(err)
+			}
+		}
+		// 清理方法在BeanDestroy之后按创建的逆序调用
+		d.cleanupLock.Lock()
+		cleanups := d.cleanups
+		d.cleanups = nil
+		d.cleanupLock.Unlock()
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			if err := runCleanup(cleanups[i]); err != nil {
+				_ = errs.AddError(err)
 			}
 		}
 		if errs.Empty() {
@@ -145,14 +299,22 @@ func (d *functionExDefinition) Destroy() error {
 	return nil
 }
 
+func runCleanup(cleanup func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Bean cleanup panic: %v ", r)
+		}
+	}()
+	cleanup()
+	return nil
+}
+
 func (d *functionExDefinition) Classify(classifier Classifier) (bool, error) {
-	d.instanceLock.RLock()
-	defer d.instanceLock.RUnlock()
 	var errs errors2.Errors
 	ok := false
-	for _, i := range d.instances.MapKeys() {
-		if !i.IsNil() {
-			ret, err := classifier.Classify(i.Interface())
+	d.scope.Scan(d.beanName, func(o reflect.Value) bool {
+		if o.IsValid() && !o.IsNil() {
+			ret, err := classifier.Classify(o.Interface())
 			if ret {
 				ok = ret
 			}
@@ -160,9 +322,46 @@ func (d *functionExDefinition) Classify(classifier Classifier) (bool, error) {
 				_ = errs.AddError(err)
 			}
 		}
-	}
+		return true
+	})
 	if errs.Empty() {
 		return ok, nil
 	}
 	return ok, errs
 }
+
+func initializeValue(ctx context.Context, o reflect.Value) error {
+	if o.IsValid() && !o.IsNil() {
+		return initializeObject(ctx, o.Interface())
+	}
+	return nil
+}
+
+func destroyValue(ctx context.Context, o reflect.Value) error {
+	if o.IsValid() && !o.IsNil() {
+		return destroyObject(ctx, o.Interface())
+	}
+	return nil
+}
+
+// 优先调用InitializingContext
+func initializeObject(ctx context.Context, o interface{}) error {
+	switch v := o.(type) {
+	case InitializingContext:
+		return v.BeanAfterSet(ctx)
+	case Initializing:
+		return v.BeanAfterSet()
+	}
+	return nil
+}
+
+// 优先调用DisposableContext
+func destroyObject(ctx context.Context, o interface{}) error {
+	switch v := o.(type) {
+	case DisposableContext:
+		return v.BeanDestroy(ctx)
+	case Disposable:
+		return v.BeanDestroy()
+	}
+	return nil
+}
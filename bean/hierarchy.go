@@ -0,0 +1,53 @@
+package bean
+
+// 具有父容器的容器，本容器中不存在的对象从父容器中查找
+type HierarchicalContainer interface {
+	Container
+
+	// 获得父容器，没有父容器时返回nil
+	Parent() Container
+}
+
+// 设置父容器：GetDefinition、Get、GetByType及GetRegisterInfo在本容器中不存在时从父容器中查找，
+// Scan只遍历本容器，遍历包括父容器在内的所有对象使用ScanHierarchy
+func OptSetParent(parent Container) ContainerOpt {
+	return func(c *defaultContainer) {
+		c.parent = parent
+	}
+}
+
+// 获得容器及其所有父容器，子容器在前
+func ContainerHierarchy(c Container) []Container {
+	var ret []Container
+	for c != nil {
+		ret = append(ret, c)
+		hc, ok := c.(HierarchicalContainer)
+		if !ok {
+			break
+		}
+		c = hc.Parent()
+	}
+	return ret
+}
+
+// 依次遍历容器及其所有父容器中的对象定义，父容器中与子容器同名的对象被子容器覆盖，不会被遍历
+func ScanHierarchy(c Container, f func(key string, value Definition) bool) {
+	seen := map[string]bool{}
+	for _, level := range ContainerHierarchy(c) {
+		stop := false
+		level.Scan(func(key string, value Definition) bool {
+			if seen[key] {
+				return true
+			}
+			seen[key] = true
+			if !f(key, value) {
+				stop = true
+				return false
+			}
+			return true
+		})
+		if stop {
+			return
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package bean
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	lazyNone int32 = iota
+	lazyInitializing
+	lazyInitialized
+	lazyFailed
+)
+
+// 延迟初始化的对象定义（bean.SetLazy），第一次获取值时才执行初始化
+type LazyDefinition interface {
+	Definition
+
+	// 获得被延迟初始化的对象定义
+	Unwrap() Definition
+
+	// 设置初始化方法，由ApplicationContext在启动时设置，未设置时获取值不会触发初始化。
+	// creation为包含当前对象定义的创建链，初始化时应通过WithCreation携带该创建链获取依赖
+	SetInitializer(f func(creation *Creation) error)
+
+	// 立即初始化（只执行一次），返回初始化的错误
+	Initialize() error
+
+	// 是否已完成初始化
+	Initialized() bool
+}
+
+type lazyDefinition struct {
+	Definition
+
+	initializer atomic.Value
+
+	state int32
+	// 初始化完成（成功或失败）时关闭
+	done chan struct{}
+	err  error
+	lock sync.Mutex
+}
+
+func newLazyDefinition(d Definition) *lazyDefinition {
+	return &lazyDefinition{
+		Definition: d,
+	}
+}
+
+func (d *lazyDefinition) Unwrap() Definition {
+	return d.Definition
+}
+
+func (d *lazyDefinition) SetInitializer(f func(creation *Creation) error) {
+	d.initializer.Store(f)
+}
+
+// 只执行一次初始化：其他调用等待初始化完成后返回相同的结果，初始化失败时之后的每次调用都返回该错误
+func (d *lazyDefinition) Initialize() error {
+	return d.initialize(nil)
+}
+
+// 初始化过程中在创建链上再次获取（循环依赖）的调用不会执行初始化，由valueIn直接返回被包装的对象定义的值
+func (d *lazyDefinition) initialize(creation *Creation) error {
+	f, _ := d.initializer.Load().(func(creation *Creation) error)
+	if f == nil {
+		return nil
+	}
+
+	d.lock.Lock()
+	switch d.state {
+	case lazyInitialized:
+		d.lock.Unlock()
+		return nil
+	case lazyFailed:
+		err := d.err
+		d.lock.Unlock()
+		return err
+	case lazyInitializing:
+		done := d.done
+		d.lock.Unlock()
+		<-done
+		d.lock.Lock()
+		defer d.lock.Unlock()
+		return d.err
+	}
+	d.state = lazyInitializing
+	d.done = make(chan struct{})
+	d.lock.Unlock()
+
+	var err error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Initialize lazy bean [%s] panic: %v ", d.Name(), r)
+			d.finish(err)
+			panic(r)
+		}
+		d.finish(err)
+	}()
+	err = f(creation.With(d))
+	return err
+}
+
+func (d *lazyDefinition) finish(err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if err != nil {
+		d.state = lazyFailed
+		d.err = err
+	} else {
+		d.state = lazyInitialized
+	}
+	close(d.done)
+}
+
+func (d *lazyDefinition) Initialized() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.state == lazyInitialized
+}
+
+func (d *lazyDefinition) Value() reflect.Value {
+	return d.valueIn(nil)
+}
+
+func (d *lazyDefinition) valueIn(creation *Creation) reflect.Value {
+	if !creation.Contains(d) {
+		if err := d.initialize(creation); err != nil {
+			panic(err)
+		}
+	}
+	return valueIn(d.Definition, creation)
+}
+
+// 未初始化的对象不需要销毁
+func (d *lazyDefinition) Destroy() error {
+	return d.DestroyContext(context.Background())
+}
+
+func (d *lazyDefinition) DestroyContext(ctx context.Context) error {
+	if !d.Initialized() {
+		return nil
+	}
+	return DestroyWithContext(ctx, d.Definition)
+}
+
+func (d *lazyDefinition) AfterSetContext(ctx context.Context) error {
+	return AfterSetWithContext(ctx, d.Definition)
+}
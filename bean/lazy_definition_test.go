@@ -0,0 +1,122 @@
+package bean
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type lazyTestBean struct {
+	ready bool
+}
+
+func newTestLazyDefinition(t *testing.T, o interface{}) *lazyDefinition {
+	d, err := CreateBeanDefinition(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newLazyDefinition(d)
+}
+
+func TestLazyDefinitionConcurrentInitialize(t *testing.T) {
+	o := &lazyTestBean{}
+	d := newTestLazyDefinition(t, o)
+	var calls int32
+	var lock sync.Mutex
+	d.SetInitializer(func(creation *Creation) error {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		lock.Lock()
+		o.ready = true
+		lock.Unlock()
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := d.Value().Interface().(*lazyTestBean)
+			lock.Lock()
+			defer lock.Unlock()
+			if !v.ready {
+				t.Error("got bean before initialization finished")
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Fatalf("initializer called %d times, expect 1", calls)
+	}
+	if !d.Initialized() {
+		t.Fatal("expect initialized")
+	}
+}
+
+func TestLazyDefinitionFailedInitialize(t *testing.T) {
+	d := newTestLazyDefinition(t, &lazyTestBean{})
+	var calls int32
+	expect := errors.New("init failed")
+	d.SetInitializer(func(creation *Creation) error {
+		atomic.AddInt32(&calls, 1)
+		return expect
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.Initialize(); err != expect {
+				t.Errorf("expect %v, but get %v", expect, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := d.Initialize(); err != expect {
+		t.Fatalf("expect %v, but get %v", expect, err)
+	}
+	if calls != 1 {
+		t.Fatalf("initializer called %d times, expect 1", calls)
+	}
+	if d.Initialized() {
+		t.Fatal("failed bean must not be initialized")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expect Value panic")
+			}
+		}()
+		d.Value()
+	}()
+}
+
+func TestLazyDefinitionReentry(t *testing.T) {
+	d := newTestLazyDefinition(t, &lazyTestBean{})
+	var inner interface{}
+	d.SetInitializer(func(creation *Creation) error {
+		// 循环依赖：初始化过程中在创建链上再次获取，返回未完成初始化的对象
+		inner = ValueOf(WithCreation(NewContainer(), creation), d).Interface()
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Initialize()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("re-entry deadlocked")
+	}
+	if inner != d.Value().Interface() {
+		t.Fatalf("expect the bean object on re-entry, but get %v", inner)
+	}
+}
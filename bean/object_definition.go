@@ -1,6 +1,7 @@
 package bean
 
 import (
+	"context"
 	"errors"
 	"github.com/ydx1011/gopher-core/reflection"
 	"reflect"
@@ -13,6 +14,8 @@ type objectDefinition struct {
 	t           reflect.Type
 	flagSet     int32
 	flagDestroy int32
+
+	proxy interface{}
 }
 
 func newObjectDefinition(o interface{}) (Definition, error) {
@@ -33,6 +36,9 @@ func newObjectDefinition(o interface{}) (Definition, error) {
 }
 
 func (d *objectDefinition) Type() reflect.Type {
+	if d.proxy != nil {
+		return reflect.TypeOf(d.proxy)
+	}
 	return d.t
 }
 
@@ -41,9 +47,17 @@ func (d *objectDefinition) Name() string {
 }
 
 func (d *objectDefinition) Value() reflect.Value {
+	if d.proxy != nil {
+		return reflect.ValueOf(d.proxy)
+	}
 	return reflect.ValueOf(d.o)
 }
 
+func (d *objectDefinition) SetProxy(o interface{}) error {
+	d.proxy = o
+	return nil
+}
+
 func (d *objectDefinition) Interface() interface{} {
 	return d.o
 }
@@ -53,21 +67,25 @@ func (d *objectDefinition) IsObject() bool {
 }
 
 func (d *objectDefinition) AfterSet() error {
+	return d.AfterSetContext(context.Background())
+}
+
+func (d *objectDefinition) AfterSetContext(ctx context.Context) error {
 	// Just run once
 	if atomic.CompareAndSwapInt32(&d.flagSet, 0, 1) {
-		if v, ok := d.o.(Initializing); ok {
-			return v.BeanAfterSet()
-		}
+		return initializeObject(ctx, d.o)
 	}
 	return nil
 }
 
 func (d *objectDefinition) Destroy() error {
+	return d.DestroyContext(context.Background())
+}
+
+func (d *objectDefinition) DestroyContext(ctx context.Context) error {
 	// Just run once
 	if atomic.CompareAndSwapInt32(&d.flagDestroy, 0, 1) {
-		if v, ok := d.o.(Disposable); ok {
-			return v.BeanDestroy()
-		}
+		return destroyObject(ctx, d.o)
 	}
 	return nil
 }
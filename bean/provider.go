@@ -0,0 +1,71 @@
+package bean
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// 延迟解析对象的方法，规则与注入一致
+// name：注入名称，t：对象类型
+type ProviderResolver func(name string, t reflect.Type) (reflect.Value, error)
+
+// 延迟注入的对象，注入字段或创建bean方法参数的类型（或其指针）实现该接口时，injector不会立即注入对象，
+// 而是设置解析方法，在使用时才从容器中获取对象。延迟注入不参与依赖分析，可用于打破创建时的循环依赖。
+type LazyInjectable interface {
+	// 设置解析方法
+	// name：注入名称，resolver：解析方法
+	SetResolver(name string, resolver ProviderResolver)
+}
+
+var (
+	LazyInjectableType = reflect.TypeOf((*LazyInjectable)(nil)).Elem()
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// 判断类型是否为延迟注入的类型
+func IsLazyInjectable(t reflect.Type) bool {
+	return t.Implements(LazyInjectableType) || reflect.PtrTo(t).Implements(LazyInjectableType)
+}
+
+// 非泛型的对象提供者，每次Get时根据注入名称从容器中获取对象，因此注入时必须指定名称，如：
+// Provider bean.ObjectProvider `inject:"myBean"`
+type ObjectProvider struct {
+	name     string
+	resolver ProviderResolver
+}
+
+func (p *ObjectProvider) SetResolver(name string, resolver ProviderResolver) {
+	p.name = name
+	p.resolver = resolver
+}
+
+// 获得对象，对象不存在或创建失败时返回错误
+func (p *ObjectProvider) Get() (o interface{}, err error) {
+	if p.resolver == nil {
+		return nil, errors.New("ObjectProvider is not injected. ")
+	}
+	if p.name == "" {
+		return nil, errors.New("ObjectProvider must be injected with bean name. ")
+	}
+	// 获取延迟初始化或方法创建的对象失败时对象定义会panic
+	defer func() {
+		if r := recover(); r != nil {
+			o, err = nil, fmt.Errorf("ObjectProvider get %s failed: %v ", p.name, r)
+		}
+	}()
+	v, err := p.resolver(p.name, emptyInterfaceType)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// 获得对象，对象不存在或创建失败时返回nil
+func (p *ObjectProvider) GetIfAvailable() interface{} {
+	o, err := p.Get()
+	if err != nil {
+		return nil
+	}
+	return o
+}
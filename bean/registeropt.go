@@ -1,15 +1,70 @@
 package bean
 
+import "time"
+
 const (
-	KeySetOrder = "register.bean.order"
+	KeySetOrder       = "register.bean.order"
+	KeySetScope       = "register.bean.scope"
+	KeySetPrimary     = "register.bean.primary"
+	KeySetQualifiers  = "register.bean.qualifiers"
+	KeySetConditions  = "register.bean.conditions"
+	KeySetRefreshable = "register.bean.refreshable"
+	KeySetLazy        = "register.bean.lazy"
+
+	KeySetInitTimeout    = "register.bean.initTimeout"
+	KeySetDestroyTimeout = "register.bean.destroyTimeout"
 )
 
+// bean注册时的配置信息
+type RegisterInfo struct {
+	// 注入顺序
+	Order int
+
+	// 作用域名称
+	Scope string
+
+	// 是否为自动注入的首选对象
+	Primary bool
+
+	// 限定符，可通过tag如inject:"@fast"选择注入
+	Qualifiers []string
+
+	// 配置更新时是否重新填充配置属性
+	Refreshable bool
+
+	// 是否延迟初始化
+	Lazy bool
+
+	// 初始化（BeanAfterSet）超时时间，0表示不限制
+	InitTimeout time.Duration
+
+	// 销毁（BeanDestroy）超时时间，0表示仅受gopher.application.shutdownTimeout限制
+	DestroyTimeout time.Duration
+}
+
+// 是否包含限定符qualifier
+func (info RegisterInfo) HasQualifier(qualifier string) bool {
+	for _, q := range info.Qualifiers {
+		if q == qualifier {
+			return true
+		}
+	}
+	return false
+}
+
 type Setter interface {
 	Set(key string, value interface{})
 }
 
 // Bean注册配置，已支持的配置有：
 // * bean.SetOrder(int) 配置bean注入顺序
+// * bean.SetScope(string) 配置bean作用域
+// * bean.SetPrimary() 配置bean为自动注入的首选对象
+// * bean.SetQualifiers(...string) 配置bean的限定符
+// * bean.SetRefreshable() 配置bean在配置更新时重新填充配置属性
+// * bean.SetLazy() 配置bean延迟初始化
+// * bean.SetInitTimeout(time.Duration)、bean.SetDestroyTimeout(time.Duration) 配置bean初始化及销毁的超时时间
+// * bean.OnCondition(...Condition)、bean.OnProperty、bean.OnMissingBean、bean.OnBean、bean.OnProfile 配置bean的注册条件
 type RegisterOpt func(setter Setter)
 
 // 配置bean注入顺序
@@ -18,3 +73,58 @@ func SetOrder(order int) RegisterOpt {
 		setter.Set(KeySetOrder, order)
 	}
 }
+
+// 配置bean作用域，内置的作用域有bean.ScopeSingleton（默认）、bean.ScopePrototype及bean.ScopeTrackedPrototype，
+// 也可以使用通过ScopeRegistry.RegisterScope（如ApplicationContext.RegisterScope）注册的自定义作用域名称。
+// 注意：仅通过方法（func() TYPE）注册的bean支持非单例作用域。
+func SetScope(scope string) RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetScope, scope)
+	}
+}
+
+// 配置bean为自动注入的首选对象，当自动注入匹配到多个对象时选择该对象
+func SetPrimary() RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetPrimary, true)
+	}
+}
+
+// 配置bean的限定符，注入时可以通过tag选择包含限定符的对象，如：
+// inject:"@fast"
+func SetQualifiers(qualifiers ...string) RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetQualifiers, qualifiers)
+	}
+}
+
+// 配置bean在配置更新（ApplicationContext.Refresh）时重新填充配置属性：
+// 重新执行ValueProcessor等实现processor.RefreshProcessor的处理器，bean.BindConfig注册的对象重新绑定并校验
+func SetRefreshable() RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetRefreshable, true)
+	}
+}
+
+// 配置bean延迟初始化：启动时不注入及初始化，在第一次被获取（GetBean、Provider等）或被非延迟初始化的bean依赖时才初始化。
+// 已初始化的bean在关闭时正常销毁。
+func SetLazy() RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetLazy, true)
+	}
+}
+
+// 配置bean初始化（BeanAfterSet）的超时时间，超时后InitializingContext的ctx被取消，初始化返回超时错误
+func SetInitTimeout(timeout time.Duration) RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetInitTimeout, timeout)
+	}
+}
+
+// 配置bean销毁（BeanDestroy）的超时时间，超时后DisposableContext的ctx被取消，容器不再等待该bean而继续关闭。
+// 同时受gopher.application.shutdownTimeout限制
+func SetDestroyTimeout(timeout time.Duration) RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetDestroyTimeout, timeout)
+	}
+}
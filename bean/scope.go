@@ -0,0 +1,219 @@
+package bean
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+const (
+	// 单例：容器中每个bean名称只创建一个对象（默认）
+	ScopeSingleton = "singleton"
+	// 多例：每次获取（注入）时都创建新的对象，容器不持有创建的对象，也不负责销毁
+	ScopePrototype = "prototype"
+	// 需要销毁的多例：每次获取时都创建新的对象，容器持有所有创建的对象直到关闭，关闭时回调BeanDestroy及清理方法。
+	// 注意：对象在容器关闭前不会被回收，频繁获取时内存会持续增长
+	ScopeTrackedPrototype = "trackedPrototype"
+)
+
+// bean作用域，决定由方法创建的bean对象的复用方式。
+// 可通过实现该接口并注册到Container自定义作用域（如按请求、按租户等）。
+type Scope interface {
+	// 作用域名称，与bean.SetScope配置的名称对应
+	Name() string
+
+	// 获得bean名称为name的对象，作用域中不存在时调用factory创建
+	// 注意：factory可能会间接获取其他bean，实现时不能在调用factory时持有全局锁。
+	// 创建过程中对同一对象的重入（循环依赖）由对象定义通过创建链检测，不会再调用Get
+	Get(name string, factory func() (reflect.Value, error)) (reflect.Value, error)
+
+	// 遍历作用域中名称为name的bean已创建的对象，用于对象的初始化及分类
+	// 返回true则继续遍历，返回false停止遍历。
+	Scan(name string, f func(o reflect.Value) bool)
+
+	// 从作用域中移除名称为name的bean所有对象并返回，返回的对象将被销毁
+	Remove(name string) []reflect.Value
+}
+
+// 不持有所创建对象的作用域，容器关闭时不会销毁其中的对象，创建对象时返回的清理方法也不会被保存。
+// 对象创建后立即初始化
+type UntrackedScope interface {
+	// 返回true表示不持有所创建的对象
+	Untracked() bool
+}
+
+func isUntracked(scope Scope) bool {
+	if v, ok := scope.(UntrackedScope); ok {
+		return v.Untracked()
+	}
+	return false
+}
+
+// 支持作用域的对象定义
+type ScopedDefinition interface {
+	// 设置对象定义注册的名称及其作用域
+	SetScope(name string, scope Scope) error
+
+	// 获得对象定义的作用域
+	Scope() Scope
+}
+
+type singletonScope struct {
+	objects  map[string]reflect.Value
+	creating map[string]*singletonCreation
+	lock     sync.RWMutex
+}
+
+// 正在创建的单例
+type singletonCreation struct {
+	done chan struct{}
+	v    reflect.Value
+	err  error
+}
+
+// 创建单例作用域
+// 同一名称的对象同一时间只有一个协程在创建，其他协程等待创建完成后获得同一对象（或同一错误）
+func NewSingletonScope() *singletonScope {
+	return &singletonScope{
+		objects:  map[string]reflect.Value{},
+		creating: map[string]*singletonCreation{},
+	}
+}
+
+func (s *singletonScope) Name() string {
+	return ScopeSingleton
+}
+
+func (s *singletonScope) Get(name string, factory func() (reflect.Value, error)) (reflect.Value, error) {
+	s.lock.RLock()
+	v, ok := s.objects[name]
+	s.lock.RUnlock()
+	if ok {
+		return v, nil
+	}
+
+	s.lock.Lock()
+	if o, ok := s.objects[name]; ok {
+		s.lock.Unlock()
+		return o, nil
+	}
+	if c, ok := s.creating[name]; ok {
+		s.lock.Unlock()
+		<-c.done
+		return c.v, c.err
+	}
+	c := &singletonCreation{
+		done: make(chan struct{}),
+	}
+	s.creating[name] = c
+	s.lock.Unlock()
+
+	finished := false
+	defer func() {
+		if !finished {
+			// factory panic，等待的协程获得错误
+			r := recover()
+			c.err = fmt.Errorf("Create singleton bean [%s] panic: %v ", name, r)
+			s.finish(name, c)
+			panic(r)
+		}
+	}()
+	c.v, c.err = factory()
+	finished = true
+	s.finish(name, c)
+	return c.v, c.err
+}
+
+func (s *singletonScope) finish(name string, c *singletonCreation) {
+	s.lock.Lock()
+	delete(s.creating, name)
+	if c.err == nil {
+		s.objects[name] = c.v
+	}
+	s.lock.Unlock()
+	close(c.done)
+}
+
+func (s *singletonScope) Scan(name string, f func(o reflect.Value) bool) {
+	s.lock.RLock()
+	v, ok := s.objects[name]
+	s.lock.RUnlock()
+	if ok {
+		f(v)
+	}
+}
+
+func (s *singletonScope) Remove(name string) []reflect.Value {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if v, ok := s.objects[name]; ok {
+		delete(s.objects, name)
+		return []reflect.Value{v}
+	}
+	return nil
+}
+
+type prototypeScope struct {
+	name    string
+	track   bool
+	objects map[string][]reflect.Value
+	lock    sync.RWMutex
+}
+
+// 创建多例作用域，作用域不持有创建的对象，对象由使用方负责销毁
+func NewPrototypeScope() *prototypeScope {
+	return &prototypeScope{
+		name: ScopePrototype,
+	}
+}
+
+// 创建需要销毁的多例作用域（bean.ScopeTrackedPrototype）
+// 注意：为了能够在销毁阶段回调BeanDestroy，作用域会持有所有创建的对象直到容器关闭。
+func NewTrackedPrototypeScope() *prototypeScope {
+	return &prototypeScope{
+		name:    ScopeTrackedPrototype,
+		track:   true,
+		objects: map[string][]reflect.Value{},
+	}
+}
+
+func (s *prototypeScope) Name() string {
+	return s.name
+}
+
+func (s *prototypeScope) Untracked() bool {
+	return !s.track
+}
+
+func (s *prototypeScope) Get(name string, factory func() (reflect.Value, error)) (reflect.Value, error) {
+	v, err := factory()
+	if err != nil || !s.track {
+		return v, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.objects[name] = append(s.objects[name], v)
+	return v, nil
+}
+
+func (s *prototypeScope) Scan(name string, f func(o reflect.Value) bool) {
+	s.lock.RLock()
+	values := s.objects[name]
+	s.lock.RUnlock()
+	for _, v := range values {
+		if !f(v) {
+			break
+		}
+	}
+}
+
+func (s *prototypeScope) Remove(name string) []reflect.Value {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ret := s.objects[name]
+	delete(s.objects, name)
+	return ret
+}
@@ -0,0 +1,170 @@
+package bean
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type scopeTestBean struct {
+	destroyed int32
+}
+
+func (b *scopeTestBean) BeanDestroy() error {
+	atomic.AddInt32(&b.destroyed, 1)
+	return nil
+}
+
+func newTestFunctionDefinition(t *testing.T, f interface{}, scope Scope) *functionExDefinition {
+	d, err := newFunctionExDefinition(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := d.(*functionExDefinition)
+	if err := ret.SetScope("test", scope); err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func TestSingletonScopeConcurrentCreate(t *testing.T) {
+	var calls int32
+	d := newTestFunctionDefinition(t, func() *scopeTestBean {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return &scopeTestBean{}
+	}, NewSingletonScope())
+
+	var wg sync.WaitGroup
+	values := make([]interface{}, 16)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					t.Error("unexpected panic: ", r)
+				}
+			}()
+			values[i] = d.Value().Interface()
+		}(i)
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Fatalf("bean function called %d times, expect 1", calls)
+	}
+	for _, v := range values {
+		if v != values[0] {
+			t.Fatal("expect the same singleton object")
+		}
+	}
+}
+
+// 参数在创建链中获取的创建方法
+type testInjectableFunction func(creation *Creation) *scopeTestBean
+
+func (f testInjectableFunction) Function() interface{} {
+	return func() *scopeTestBean {
+		return f(nil)
+	}
+}
+
+func (f testInjectableFunction) Call(creation *Creation) []reflect.Value {
+	return []reflect.Value{reflect.ValueOf(f(creation))}
+}
+
+func TestSingletonScopeReentry(t *testing.T) {
+	var d *functionExDefinition
+	d = newTestFunctionDefinition(t, testInjectableFunction(func(creation *Creation) *scopeTestBean {
+		// 创建过程中在创建链上再次获取自身
+		ValueOf(WithCreation(NewContainer(), creation), d)
+		return &scopeTestBean{}
+	}), NewSingletonScope())
+
+	func() {
+		defer func() {
+			r := recover()
+			err, ok := r.(error)
+			if !ok || !strings.Contains(err.Error(), "Circular dependency") {
+				t.Fatalf("expect circular dependency error, got %v", r)
+			}
+		}()
+		d.Value()
+	}()
+}
+
+func TestSingletonScopeFactoryPanic(t *testing.T) {
+	s := NewSingletonScope()
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("expect panic boom, got %v", r)
+			}
+		}()
+		_, _ = s.Get("test", func() (reflect.Value, error) {
+			panic("boom")
+		})
+	}()
+
+	// 失败后允许重新创建
+	v, err := s.Get("test", func() (reflect.Value, error) {
+		return reflect.ValueOf(&scopeTestBean{}), nil
+	})
+	if err != nil || !v.IsValid() {
+		t.Fatalf("expect created, got %v %v", v, err)
+	}
+}
+
+func TestPrototypeScopeTracking(t *testing.T) {
+	tests := []struct {
+		name      string
+		scope     Scope
+		objects   int
+		destroyed int32
+		cleanups  int32
+	}{
+		{name: "prototype", scope: NewPrototypeScope(), objects: 0, destroyed: 0, cleanups: 0},
+		{name: "trackedPrototype", scope: NewTrackedPrototypeScope(), objects: 3, destroyed: 1, cleanups: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cleanups int32
+			var created []*scopeTestBean
+			d := newTestFunctionDefinition(t, func() (*scopeTestBean, func(), error) {
+				o := &scopeTestBean{}
+				created = append(created, o)
+				return o, func() { atomic.AddInt32(&cleanups, 1) }, nil
+			}, tt.scope)
+			if err := d.AfterSet(); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 3; i++ {
+				d.Value()
+			}
+
+			objects := 0
+			tt.scope.Scan("test", func(o reflect.Value) bool {
+				objects++
+				return true
+			})
+			if objects != tt.objects {
+				t.Fatalf("scope holds %d objects, expect %d", objects, tt.objects)
+			}
+
+			if err := d.Destroy(); err != nil {
+				t.Fatal(err)
+			}
+			for _, o := range created {
+				if o.destroyed != tt.destroyed {
+					t.Fatalf("object destroyed %d times, expect %d", o.destroyed, tt.destroyed)
+				}
+			}
+			if cleanups != tt.cleanups {
+				t.Fatalf("cleanup called %d times, expect %d", cleanups, tt.cleanups)
+			}
+		})
+	}
+}
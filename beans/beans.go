@@ -0,0 +1,151 @@
+// Package beans 提供基于泛型的类型安全的bean注册及查找方法。
+// bean名称使用reflection.GetTypeName生成，与tag注入（inject）的名称规则保持一致。
+package beans
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/injector"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+)
+
+// bean上下文，appcontext.NewDefaultApplicationContext创建的context实现了该接口，
+// 持有appcontext.ApplicationContext时可通过类型断言获得
+type Context interface {
+	// 使用指定名称注册对象
+	RegisterBeanByName(name string, o interface{}, opts ...bean.RegisterOpt) error
+
+	// 获得bean容器
+	GetContainer() bean.Container
+}
+
+// 根据类型T获得bean：
+// 1、优先获取以T的类型名称注册（或已被注入缓存）的bean；
+// 2、T为interface时自动匹配实现T的bean（指定名称注册的bean不参与匹配），
+// 匹配到多个时选择标记为首选（bean.SetPrimary）的bean，仍无法确定时返回错误。
+func Get[T any](ctx Context) (T, error) {
+	var zero T
+	t := typeOf[T]()
+	c := ctx.GetContainer()
+	name := reflection.GetTypeName(t)
+	if _, ok := c.GetDefinition(name); ok {
+		return GetNamed[T](ctx, name)
+	}
+	if t.Kind() != reflect.Interface {
+		return zero, fmt.Errorf("Bean type [%s] not found ", name)
+	}
+
+	key, err := injector.SelectCandidate(c, t, injector.FindCandidates(c, t, ""))
+	if err != nil {
+		return zero, err
+	}
+	return GetNamed[T](ctx, key)
+}
+
+// 根据类型T获得bean，失败时panic
+func MustGet[T any](ctx Context) T {
+	v, err := Get[T](ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// 根据限定符获得bean，匹配到多个时选择标记为首选的bean
+func GetQualified[T any](ctx Context, qualifier string) (T, error) {
+	var zero T
+	t := typeOf[T]()
+	c := ctx.GetContainer()
+	key, err := injector.SelectCandidate(c, t, injector.FindCandidates(c, t, qualifier))
+	if err != nil {
+		return zero, err
+	}
+	return GetNamed[T](ctx, key)
+}
+
+// 根据名称获得bean，bean不存在或类型不是T时返回错误
+func GetNamed[T any](ctx Context, name string) (ret T, err error) {
+	d, ok := ctx.GetContainer().GetDefinition(name)
+	if !ok {
+		return ret, fmt.Errorf("Bean [%s] not found ", name)
+	}
+	v, err := value(d)
+	if err != nil {
+		return ret, err
+	}
+	if !v.IsValid() {
+		return ret, fmt.Errorf("Bean [%s] is nil ", name)
+	}
+	ret, ok = v.Interface().(T)
+	if !ok {
+		return ret, fmt.Errorf("Bean [%s] type [%s] is not [%s] ", name,
+			reflection.GetTypeName(v.Type()), reflection.GetTypeName(typeOf[T]()))
+	}
+	return ret, nil
+}
+
+// 获得所有类型能够赋值给T的bean（包括父容器中的bean），按注册顺序返回
+func GetAll[T any](ctx Context) []T {
+	t := typeOf[T]()
+	var (
+		ret  []T
+		defs []bean.Definition
+	)
+	seen := map[bean.Definition]bool{}
+	bean.ScanHierarchy(ctx.GetContainer(), func(key string, value bean.Definition) bool {
+		// 同一个对象定义可能以多个名称（如interface名称）缓存在容器中
+		if !seen[value] && value.Type().AssignableTo(t) {
+			seen[value] = true
+			defs = append(defs, value)
+		}
+		return true
+	})
+	for _, d := range defs {
+		v, err := value(d)
+		if err == nil && v.IsValid() {
+			if o, ok := v.Interface().(T); ok {
+				ret = append(ret, o)
+			}
+		}
+	}
+	return ret
+}
+
+// 创建类型为T的bean的方法，返回值形式与bean.VerifyBeanFunction一致
+type Factory[T any] interface {
+	~func() T | ~func() (T, error) | ~func() (T, func(), error)
+}
+
+// 以类型T的名称注册创建bean的方法，factory的返回值类型必须为T，如：
+// beans.Register[Service](ctx, func() Service { return &serviceImpl{} })
+// 创建时需要注入依赖的方法使用RegisterBean注册，或通过Provider[T]等延迟注入类型获取依赖
+func Register[T any, F Factory[T]](ctx Context, factory F, opts ...bean.RegisterOpt) error {
+	if reflect.ValueOf(factory).IsNil() {
+		return errors.New("Bean factory is nil. ")
+	}
+	return ctx.RegisterBeanByName(reflection.GetTypeName(typeOf[T]()), factory, opts...)
+}
+
+// 以类型T的名称注册对象，对象必须为指针（T为interface时为其实现对象的指针）
+func RegisterObject[T any](ctx Context, o T, opts ...bean.RegisterOpt) error {
+	v := reflect.ValueOf(o)
+	if !v.IsValid() || v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("Bean object of %s must be a non-nil pointer ", reflection.GetTypeName(typeOf[T]()))
+	}
+	return ctx.RegisterBeanByName(reflection.GetTypeName(typeOf[T]()), o, opts...)
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func value(d bean.Definition) (v reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Get bean [%s] failed: %v ", d.Name(), r)
+		}
+	}()
+	return d.Value(), nil
+}
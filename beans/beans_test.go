@@ -0,0 +1,173 @@
+package beans
+
+import (
+	"errors"
+	"github.com/ydx1011/gopher-core/bean"
+	"testing"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type englishGreeter struct{}
+
+func (g *englishGreeter) Greet() string {
+	return "hello"
+}
+
+type chineseGreeter struct{}
+
+func (g *chineseGreeter) Greet() string {
+	return "你好"
+}
+
+type store struct {
+	closed bool
+}
+
+func TestRegisterAndGet(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(ctx Context) error
+		expect   string
+	}{
+		{
+			name: "factory",
+			register: func(ctx Context) error {
+				return Register[greeter](ctx, func() greeter { return &englishGreeter{} })
+			},
+			expect: "hello",
+		},
+		{
+			name: "factory with error",
+			register: func(ctx Context) error {
+				return Register[greeter](ctx, func() (greeter, error) { return &chineseGreeter{}, nil })
+			},
+			expect: "你好",
+		},
+		{
+			name: "object",
+			register: func(ctx Context) error {
+				return RegisterObject[greeter](ctx, &englishGreeter{})
+			},
+			expect: "hello",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t)
+			defer ctx.Close()
+			if err := tt.register(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if err := ctx.Start(); err != nil {
+				t.Fatal(err)
+			}
+			g, err := Get[greeter](ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g.Greet() != tt.expect {
+				t.Fatalf("expect %s, got %s", tt.expect, g.Greet())
+			}
+		})
+	}
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.Close()
+	var nilFactory func() greeter
+	if err := Register[greeter](ctx, nilFactory); err == nil {
+		t.Fatal("expect nil factory error")
+	}
+	if err := RegisterObject[greeter](ctx, nil); err == nil {
+		t.Fatal("expect nil object error")
+	}
+	if err := RegisterObject[store](ctx, store{}); err == nil {
+		t.Fatal("expect non pointer object error")
+	}
+}
+
+func TestRegisterFactoryError(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.Close()
+	_ = Register[*store](ctx, func() (*store, error) {
+		return nil, errors.New("open failed")
+	}, bean.SetLazy())
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get[*store](ctx); err == nil {
+		t.Fatal("expect factory error")
+	}
+}
+
+func TestGetInterface(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []bean.RegisterOpt
+		expect string
+		err    bool
+	}{
+		{name: "ambiguous", err: true},
+		{name: "primary", opts: []bean.RegisterOpt{bean.SetPrimary()}, expect: "你好"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t)
+			defer ctx.Close()
+			_ = ctx.RegisterBean(&englishGreeter{}, bean.SetQualifiers("en"))
+			_ = ctx.RegisterBean(&chineseGreeter{}, append(tt.opts, bean.SetQualifiers("zh"))...)
+			if err := ctx.Start(); err != nil {
+				t.Fatal(err)
+			}
+			g, err := Get[greeter](ctx)
+			if tt.err {
+				if err == nil {
+					t.Fatal("expect ambiguous error")
+				}
+			} else if err != nil || g.Greet() != tt.expect {
+				t.Fatalf("expect %s, got %v %v", tt.expect, g, err)
+			}
+
+			q, err := GetQualified[greeter](ctx, "en")
+			if err != nil || q.Greet() != "hello" {
+				t.Fatalf("expect qualified bean, got %v %v", q, err)
+			}
+			if all := GetAll[greeter](ctx); len(all) != 2 {
+				t.Fatalf("expect 2 greeters, got %d", len(all))
+			}
+		})
+	}
+}
+
+func TestGetNamed(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.Close()
+	_ = ctx.RegisterBeanByName("en", &englishGreeter{})
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if g, err := GetNamed[greeter](ctx, "en"); err != nil || g.Greet() != "hello" {
+		t.Fatalf("expect named bean, got %v %v", g, err)
+	}
+	if _, err := GetNamed[greeter](ctx, "missing"); err == nil {
+		t.Fatal("expect missing bean error")
+	}
+	if _, err := GetNamed[*store](ctx, "en"); err == nil {
+		t.Fatal("expect type mismatch error")
+	}
+	if _, err := Get[*store](ctx); err == nil {
+		t.Fatal("expect not found error")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expect MustGet panic")
+			}
+		}()
+		MustGet[*store](ctx)
+	}()
+}
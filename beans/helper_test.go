@@ -0,0 +1,25 @@
+package beans
+
+import (
+	"github.com/ydx1011/gopher-core/appcontext"
+	"github.com/ydx1011/yfig"
+	"testing"
+)
+
+type testContext interface {
+	appcontext.ApplicationContext
+	Context
+}
+
+func newTestContext(t *testing.T) testContext {
+	ctx := appcontext.NewDefaultApplicationContext()
+	v := yfig.Value{"gopher": map[string]interface{}{
+		"application": map[string]interface{}{"bannerMode": "off"},
+	}}
+	prop := yfig.New()
+	prop.Value = &v
+	if err := ctx.Init(prop); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
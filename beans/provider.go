@@ -0,0 +1,76 @@
+package beans
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/reflection"
+	"sync"
+)
+
+// 类型为T的对象提供者，作为注入字段或创建bean方法的参数时不会立即注入，每次Get时从容器中获取对象（多例每次获得新的对象），
+// 不参与依赖分析，可用于打破创建时的循环依赖。注入名称（包括限定符）的规则与T类型的字段一致，如：
+// Cache beans.Provider[Cache] `inject:"@redis"`
+type Provider[T any] struct {
+	name     string
+	resolver bean.ProviderResolver
+}
+
+func (p *Provider[T]) SetResolver(name string, resolver bean.ProviderResolver) {
+	p.name = name
+	p.resolver = resolver
+}
+
+// 获得对象，对象不存在时返回错误
+func (p *Provider[T]) Get() (ret T, err error) {
+	if p.resolver == nil {
+		return ret, errors.New("Provider is not injected. ")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Provider get %s failed: %v ", reflection.GetTypeName(typeOf[T]()), r)
+		}
+	}()
+	v, err := p.resolver(p.name, typeOf[T]())
+	if err != nil {
+		return ret, err
+	}
+	return v.Interface().(T), nil
+}
+
+// 获得对象，对象不存在时返回false
+func (p *Provider[T]) GetIfAvailable() (T, bool) {
+	v, err := p.Get()
+	return v, err == nil
+}
+
+// 类型为T的延迟对象，与Provider一致，但只在第一次成功获取时从容器中获取对象，之后返回同一个对象
+type Lazy[T any] struct {
+	Provider[T]
+
+	v      T
+	loaded bool
+	lock   sync.Mutex
+}
+
+// 获得对象，对象不存在时返回错误
+func (l *Lazy[T]) Get() (T, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.loaded {
+		return l.v, nil
+	}
+	v, err := l.Provider.Get()
+	if err != nil {
+		return v, err
+	}
+	l.v, l.loaded = v, true
+	return v, nil
+}
+
+// 获得对象，对象不存在时返回false
+func (l *Lazy[T]) GetIfAvailable() (T, bool) {
+	v, err := l.Get()
+	return v, err == nil
+}
@@ -0,0 +1,133 @@
+package beans
+
+import (
+	"errors"
+	"github.com/ydx1011/gopher-core/bean"
+	"testing"
+)
+
+type providerMissing struct{}
+
+type providerFailing struct{}
+
+type providerHolder struct {
+	Missing       Provider[*providerMissing] `inject:""`
+	LazyMissing   Lazy[*providerMissing]     `inject:""`
+	ObjectMissing bean.ObjectProvider        `inject:"providerMissing"`
+
+	Failing       Provider[*providerFailing] `inject:""`
+	LazyFailing   Lazy[*providerFailing]     `inject:""`
+	ObjectFailing bean.ObjectProvider        `inject:"providerFailing"`
+}
+
+func TestProviderGetErrors(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.Close()
+	h := &providerHolder{}
+	_ = ctx.RegisterBean(h)
+	_ = ctx.RegisterBeanByName("providerFailing", func() (*providerFailing, error) {
+		return nil, errors.New("create failed")
+	}, bean.SetScope(bean.ScopePrototype))
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		get  func() (interface{}, error)
+		ok   func() bool
+	}{
+		{
+			name: "provider missing",
+			get:  func() (interface{}, error) { return h.Missing.Get() },
+			ok:   func() bool { _, ok := h.Missing.GetIfAvailable(); return ok },
+		},
+		{
+			name: "lazy missing",
+			get:  func() (interface{}, error) { return h.LazyMissing.Get() },
+			ok:   func() bool { _, ok := h.LazyMissing.GetIfAvailable(); return ok },
+		},
+		{
+			name: "object provider missing",
+			get:  h.ObjectMissing.Get,
+			ok:   func() bool { return h.ObjectMissing.GetIfAvailable() != nil },
+		},
+		{
+			name: "provider failing factory",
+			get:  func() (interface{}, error) { return h.Failing.Get() },
+			ok:   func() bool { _, ok := h.Failing.GetIfAvailable(); return ok },
+		},
+		{
+			name: "lazy failing factory",
+			get:  func() (interface{}, error) { return h.LazyFailing.Get() },
+			ok:   func() bool { _, ok := h.LazyFailing.GetIfAvailable(); return ok },
+		},
+		{
+			name: "object provider failing factory",
+			get:  h.ObjectFailing.Get,
+			ok:   func() bool { return h.ObjectFailing.GetIfAvailable() != nil },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("unexpected panic: %v", r)
+				}
+			}()
+			if _, err := tt.get(); err == nil {
+				t.Fatal("expect error")
+			}
+			if tt.ok() {
+				t.Fatal("expect not available")
+			}
+		})
+	}
+}
+
+type cycleA struct {
+	B Provider[*cycleB]
+}
+
+type cycleB struct {
+	A *cycleA
+}
+
+type lazyCycleA struct {
+	B *Lazy[*cycleB]
+}
+
+func TestProviderBreakConstructionCycle(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.Close()
+	_ = ctx.RegisterBean(func(b Provider[*cycleB]) *cycleA {
+		return &cycleA{B: b}
+	})
+	_ = ctx.RegisterBean(func(a *cycleA) *cycleB {
+		return &cycleB{A: a}
+	})
+	_ = ctx.RegisterBean(func(b *Lazy[*cycleB]) *lazyCycleA {
+		return &lazyCycleA{B: b}
+	})
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	a := MustGet[*cycleA](ctx)
+	b, err := a.B.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.A != a {
+		t.Fatal("expect cycle resolved through provider")
+	}
+
+	la := MustGet[*lazyCycleA](ctx)
+	lb, err := la.B.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := la.B.Get(); again != lb || lb != b {
+		t.Fatal("expect lazy returns the same singleton")
+	}
+}
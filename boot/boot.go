@@ -3,7 +3,9 @@ package boot
 import (
 	"flag"
 	"github.com/ydx1011/gopher-core"
+	"github.com/ydx1011/gopher-core/appcontext"
 	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/config"
 	"os"
 	"sync"
 )
@@ -13,6 +15,10 @@ const EnvNameConfigFile = "GOPHER_CONFIG_FILE"
 var (
 	// 默认的配置路径
 	ConfigPath = "application.yaml"
+	// 激活的profile，多个profile使用逗号分隔
+	Profiles = ""
+	// 启动完成后导出bean依赖图的文件路径，扩展名为.json时导出JSON，否则导出Graphviz DOT
+	BeansGraph = ""
 
 	creator func() gopher.Application = defaultCreator
 	gApp    gopher.Application
@@ -48,12 +54,37 @@ func Customize(app gopher.Application) {
 }
 
 func defaultCreator() gopher.Application {
+	return gopher.NewApplicationWithSources(parseCommandLine(flag.CommandLine, os.Args[1:]))
+}
+
+// 使用fs解析启动参数（-f、-p、-beans-graph及形如--gopher.application.name=x的配置属性），
+// 返回配置文件、环境变量及命令行参数组成的配置属性来源
+func parseCommandLine(fs *flag.FlagSet, arguments []string) *config.PropertySources {
 	if conf, ok := os.LookupEnv(EnvNameConfigFile); ok {
 		ConfigPath = conf
 	}
-	flag.StringVar(&ConfigPath, "f", ConfigPath, "Application configuration file path.")
-	flag.Parse()
-	return gopher.NewFileConfigApplication(ConfigPath)
+	if profiles, ok := os.LookupEnv(config.EnvProfilesActive); ok {
+		Profiles = profiles
+	}
+	fs.StringVar(&ConfigPath, "f", ConfigPath, "Application configuration file path.")
+	fs.StringVar(&Profiles, "p", Profiles, "Active profiles, split by ','.")
+	fs.StringVar(&BeansGraph, "beans-graph", BeansGraph, "Export beans graph after started, .json for JSON, otherwise Graphviz DOT.")
+	// 形如--gopher.application.name=x的参数作为配置属性，优先级最高
+	props, args := config.SplitCommandLineArgs(arguments)
+	_ = fs.Parse(args)
+	if v, ok := props[config.KeyProfilesActive]; ok && Profiles == "" {
+		Profiles = v
+	}
+	cmdArgs := arguments
+	if BeansGraph != "" {
+		// -beans-graph参数作为命令行属性，覆盖--gopher.application.beansGraph
+		cmdArgs = append(append([]string{}, cmdArgs...), "--"+appcontext.KeyBeansGraph+"="+BeansGraph)
+	}
+	return config.NewPropertySources(
+		config.NewFileSource(ConfigPath, config.ParseProfiles(Profiles)...),
+		config.NewEnvSource(config.DefaultEnvPrefix),
+		config.NewCommandLineSource(cmdArgs),
+	)
 }
 
 func instance() gopher.Application {
@@ -0,0 +1,70 @@
+package boot
+
+import (
+	"flag"
+	"github.com/ydx1011/gopher-core/appcontext"
+	"github.com/ydx1011/gopher-core/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCommandLine(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "application.yaml")
+	files := map[string]string{
+		path: "app:\n  name: base\n",
+		filepath.Join(dir, "application-dev.yaml"): "app:\n  name: dev\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		configEnv  string
+		appName    string
+		beansGraph string
+	}{
+		{name: "config file", args: []string{"-f", path}, appName: "base"},
+		{name: "config file env", configEnv: path, appName: "base"},
+		{name: "profile flag", args: []string{"-f", path, "-p", "dev"}, appName: "dev"},
+		{name: "profile property", args: []string{"-f", path, "--gopher.profiles.active=dev"}, appName: "dev"},
+		// 命令行配置属性覆盖配置文件
+		{name: "property", args: []string{"-f", path, "--app.name=cmd"}, appName: "cmd"},
+		{name: "beans graph property", args: []string{"-f", path, "--gopher.application.beansGraph=beans.dot"}, appName: "base", beansGraph: "beans.dot"},
+		// -beans-graph参数覆盖--gopher.application.beansGraph
+		{
+			name:       "beans graph flag",
+			args:       []string{"-f", path, "-beans-graph", "beans.json", "--gopher.application.beansGraph=beans.dot"},
+			appName:    "base",
+			beansGraph: "beans.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ConfigPath, Profiles, BeansGraph = "application.yaml", "", ""
+			t.Setenv(config.EnvProfilesActive, "")
+			_ = os.Unsetenv(config.EnvProfilesActive)
+			t.Setenv(EnvNameConfigFile, tt.configEnv)
+			if tt.configEnv == "" {
+				_ = os.Unsetenv(EnvNameConfigFile)
+			}
+
+			sources := parseCommandLine(flag.NewFlagSet("test", flag.ContinueOnError), tt.args)
+			prop, err := sources.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v := prop.Get("app.name", ""); v != tt.appName {
+				t.Fatalf("expect app.name %s, got %s", tt.appName, v)
+			}
+			if v := prop.Get(appcontext.KeyBeansGraph, ""); v != tt.beansGraph {
+				t.Fatalf("expect beansGraph %q, got %q", tt.beansGraph, v)
+			}
+		})
+	}
+}
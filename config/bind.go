@@ -0,0 +1,369 @@
+package config
+
+import (
+	"fmt"
+	"github.com/ydx1011/gopher-core/errors"
+	"github.com/ydx1011/yfig"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// 指定字段绑定的属性名称，"-"表示忽略该字段，未指定时使用字段名称宽松匹配（忽略大小写、"-"及"_"）
+	TagConfig = "config"
+	// 字段校验规则，多个规则使用逗号分隔，支持：required、min=x、max=x、regex=x（regex必须为最后一个规则）
+	// min、max对数字、time.Duration及DataSize比较值，对string、slice、map比较长度
+	TagValidate = "validate"
+)
+
+// 数据大小（字节），配置支持数字或带单位的字符串，如：512、10KB、1.5MB、2G
+type DataSize int64
+
+const (
+	Byte     DataSize = 1
+	KiloByte          = 1024 * Byte
+	MegaByte          = 1024 * KiloByte
+	GigaByte          = 1024 * MegaByte
+	TeraByte          = 1024 * GigaByte
+)
+
+var dataSizeUnits = []struct {
+	suffix string
+	size   DataSize
+}{
+	{"TB", TeraByte}, {"GB", GigaByte}, {"MB", MegaByte}, {"KB", KiloByte},
+	{"T", TeraByte}, {"G", GigaByte}, {"M", MegaByte}, {"K", KiloByte}, {"B", Byte},
+}
+
+// 解析数据大小
+func ParseDataSize(s string) (DataSize, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	unit := Byte
+	for _, u := range dataSizeUnits {
+		if strings.HasSuffix(str, u.suffix) {
+			str = strings.TrimSpace(strings.TrimSuffix(str, u.suffix))
+			unit = u.size
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid data size: %s ", s)
+	}
+	return DataSize(f * float64(unit)), nil
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	dataSizeType = reflect.TypeOf(DataSize(0))
+)
+
+// 将配置中prefix下的属性绑定到o（struct指针）并按validate tag校验，prefix为空时绑定全部属性。
+// 字段规则见TagConfig、TagValidate，time.Duration支持"5s"形式的字符串，数字表示毫秒。
+// 返回的错误为errors.Errors，包含所有绑定及校验失败的字段。
+func Bind(prop yfig.Properties, prefix string, o interface{}) error {
+	v := reflect.ValueOf(o)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Bind config target must be struct pointer, but get %s ", reflect.TypeOf(o))
+	}
+
+	var raw interface{}
+	if err := prop.GetValue(prefix, &raw); err != nil {
+		// 属性不存在时保持默认值，由校验规则判断是否合法
+		raw = nil
+	}
+
+	var errs errors.Errors
+	bindValue(prefix, raw, v.Elem(), &errs)
+	validateStruct(prefix, v.Elem(), &errs)
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+func bindValue(path string, raw interface{}, v reflect.Value, errs *errors.Errors) {
+	if raw == nil {
+		return
+	}
+	fail := func() {
+		_ = errs.AddError(fmt.Errorf("%s: cannot bind %v to %s ", path, raw, v.Type()))
+	}
+
+	switch v.Type() {
+	case durationType:
+		switch r := raw.(type) {
+		case string:
+			d, err := time.ParseDuration(r)
+			if err != nil {
+				fail()
+				return
+			}
+			v.SetInt(int64(d))
+		case float64:
+			v.SetInt(int64(time.Duration(r * float64(time.Millisecond))))
+		default:
+			fail()
+		}
+		return
+	case dataSizeType:
+		s, err := ParseDataSize(fmt.Sprint(raw))
+		if err != nil {
+			fail()
+			return
+		}
+		v.SetInt(int64(s))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(fmt.Sprint(raw))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(fmt.Sprint(raw))
+		if err != nil {
+			fail()
+			return
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(formatNumber(raw), 10, 64)
+		if err != nil || v.OverflowInt(i) {
+			fail()
+			return
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(formatNumber(raw), 10, 64)
+		if err != nil || v.OverflowUint(i) {
+			fail()
+			return
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(fmt.Sprint(raw), 64)
+		if err != nil {
+			fail()
+			return
+		}
+		v.SetFloat(f)
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		bindValue(path, raw, v.Elem(), errs)
+	case reflect.Interface:
+		rv := reflect.ValueOf(raw)
+		if !rv.Type().AssignableTo(v.Type()) {
+			fail()
+			return
+		}
+		v.Set(rv)
+	case reflect.Slice:
+		var items []interface{}
+		switch r := raw.(type) {
+		case []interface{}:
+			items = r
+		case string:
+			for _, s := range strings.Split(r, ",") {
+				items = append(items, strings.TrimSpace(s))
+			}
+		default:
+			items = []interface{}{raw}
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			bindValue(fmt.Sprintf("%s[%d]", path, i), item, s.Index(i), errs)
+		}
+		v.Set(s)
+	case reflect.Map:
+		m, ok := raw.(map[string]interface{})
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			fail()
+			return
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		for k, item := range m {
+			e := reflect.New(v.Type().Elem()).Elem()
+			bindValue(joinPath(path, k), item, e, errs)
+			v.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), e)
+		}
+	case reflect.Struct:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			fail()
+			return
+		}
+		bindStruct(path, m, v, errs)
+	default:
+		fail()
+	}
+}
+
+func bindStruct(path string, m map[string]interface{}, v reflect.Value, errs *errors.Errors) {
+	keys := make(map[string]string, len(m))
+	for k := range m {
+		keys[normalizeName(k)] = k
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Tag.Get(TagConfig)
+		if name == "-" {
+			continue
+		}
+		// 未指定名称的嵌入struct与外层共用属性
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			bindStruct(path, m, v.Field(i), errs)
+			continue
+		}
+		key := name
+		if key == "" {
+			key = keys[normalizeName(field.Name)]
+		}
+		if raw, ok := m[key]; ok && key != "" {
+			bindValue(joinPath(path, key), raw, v.Field(i), errs)
+		}
+	}
+}
+
+func validateStruct(path string, v reflect.Value, errs *errors.Errors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Tag.Get(TagConfig) == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		fpath := path
+		if !field.Anonymous {
+			name := field.Tag.Get(TagConfig)
+			if name == "" {
+				name = field.Name
+			}
+			fpath = joinPath(path, name)
+		}
+		if rules := field.Tag.Get(TagValidate); rules != "" {
+			if err := validateValue(fv, rules); err != nil {
+				_ = errs.AddError(fmt.Errorf("%s: %v ", fpath, err))
+				continue
+			}
+		}
+		if fv.Kind() == reflect.Ptr && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct {
+			validateStruct(fpath, fv, errs)
+		}
+	}
+}
+
+func validateValue(v reflect.Value, rules string) error {
+	for rules != "" {
+		var rule string
+		if strings.HasPrefix(rules, "regex=") {
+			rule, rules = rules, ""
+		} else if i := strings.Index(rules, ","); i >= 0 {
+			rule, rules = rules[:i], rules[i+1:]
+		} else {
+			rule, rules = rules, ""
+		}
+		rule = strings.TrimSpace(rule)
+
+		switch {
+		case rule == "required":
+			if v.IsZero() {
+				return fmt.Errorf("is required")
+			}
+		case strings.HasPrefix(rule, "min="):
+			if ok, err := compare(v, rule[4:], func(a, b float64) bool { return a >= b }); err != nil {
+				return err
+			} else if !ok {
+				return fmt.Errorf("must not be less than %s", rule[4:])
+			}
+		case strings.HasPrefix(rule, "max="):
+			if ok, err := compare(v, rule[4:], func(a, b float64) bool { return a <= b }); err != nil {
+				return err
+			} else if !ok {
+				return fmt.Errorf("must not be greater than %s", rule[4:])
+			}
+		case strings.HasPrefix(rule, "regex="):
+			re, err := regexp.Compile(rule[6:])
+			if err != nil {
+				return fmt.Errorf("invalid regex %s: %v", rule[6:], err)
+			}
+			if v.Kind() != reflect.String {
+				return fmt.Errorf("regex only support string, but get %s", v.Type())
+			}
+			if !re.MatchString(v.String()) {
+				return fmt.Errorf("[%s] not match %s", v.String(), rule[6:])
+			}
+		case rule == "":
+		default:
+			return fmt.Errorf("unknown validate rule %s", rule)
+		}
+	}
+	return nil
+}
+
+func compare(v reflect.Value, limit string, f func(a, b float64) bool) (bool, error) {
+	var (
+		a, b float64
+		err  error
+	)
+	switch {
+	case v.Type() == durationType:
+		var d time.Duration
+		d, err = time.ParseDuration(limit)
+		a, b = float64(v.Int()), float64(d)
+	case v.Type() == dataSizeType:
+		var s DataSize
+		s, err = ParseDataSize(limit)
+		a, b = float64(v.Int()), float64(s)
+	default:
+		b, err = strconv.ParseFloat(limit, 64)
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			a = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			a = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			a = v.Float()
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			a = float64(v.Len())
+		default:
+			return false, fmt.Errorf("min/max not support %s", v.Type())
+		}
+	}
+	if err != nil {
+		return false, fmt.Errorf("invalid limit %s", limit)
+	}
+	return f(a, b), nil
+}
+
+// 数字在yaml解析后为float64，整数值去掉小数部分
+func formatNumber(raw interface{}) string {
+	if f, ok := raw.(float64); ok && f == float64(int64(f)) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return fmt.Sprint(raw)
+}
+
+func normalizeName(s string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(s))
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
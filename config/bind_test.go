@@ -0,0 +1,98 @@
+package config
+
+import (
+	"github.com/ydx1011/gopher-core/errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindServer struct {
+	Host    string        `validate:"required,regex=^[a-z.]+$"`
+	Port    int           `validate:"min=1,max=65535"`
+	Timeout time.Duration `validate:"max=10s"`
+	MaxBody DataSize      `config:"max-body" validate:"max=1MB"`
+	Tags    []string      `validate:"max=2"`
+}
+
+func TestBindValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		errs   []string
+	}{
+		{
+			name: "valid",
+			values: map[string]interface{}{
+				"host": "example.com", "port": 8080, "timeout": "5s", "max-body": "512KB", "tags": []interface{}{"a"},
+			},
+		},
+		{
+			name:   "required",
+			values: map[string]interface{}{"port": 80},
+			errs:   []string{"server.Host: is required"},
+		},
+		{
+			name:   "regex",
+			values: map[string]interface{}{"host": "Example_1", "port": 80},
+			errs:   []string{"server.Host: [Example_1] not match"},
+		},
+		{
+			name:   "min and max",
+			values: map[string]interface{}{"host": "a", "port": 70000, "timeout": "1m", "max-body": "2MB"},
+			errs: []string{
+				"server.Port: must not be greater than 65535",
+				"server.Timeout: must not be greater than 10s",
+				"server.max-body: must not be greater than 1MB",
+			},
+		},
+		{
+			name:   "slice length",
+			values: map[string]interface{}{"host": "a", "port": 80, "tags": []interface{}{"a", "b", "c"}},
+			errs:   []string{"server.Tags: must not be greater than 2"},
+		},
+		{
+			name:   "type mismatch",
+			values: map[string]interface{}{"host": "a", "port": "abc"},
+			errs:   []string{"server.port"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop := NewProperties(map[string]interface{}{"server": tt.values})
+			o := &bindServer{}
+			err := Bind(prop, "server", o)
+			if len(tt.errs) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if o.Host != "example.com" || o.Port != 8080 || o.Timeout != 5*time.Second || o.MaxBody != 512*KiloByte {
+					t.Fatalf("unexpected bound value %+v", o)
+				}
+				return
+			}
+			es, ok := err.(errors.Errors)
+			if !ok {
+				t.Fatalf("expect errors.Errors, got %v", err)
+			}
+			// 返回所有失败的字段
+			if len(es) < len(tt.errs) {
+				t.Fatalf("expect %d errors, got %v", len(tt.errs), es)
+			}
+			for _, expect := range tt.errs {
+				if !strings.Contains(es.Error(), expect) {
+					t.Fatalf("expect error contains %q, got %v", expect, es)
+				}
+			}
+		})
+	}
+}
+
+func TestBindInvalidTarget(t *testing.T) {
+	prop := NewProperties(map[string]interface{}{})
+	for _, o := range []interface{}{nil, bindServer{}, new(int)} {
+		if err := Bind(prop, "", o); err == nil {
+			t.Fatalf("expect error for target %T", o)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"github.com/xfali/xlog"
+	"github.com/ydx1011/yfig"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// 激活profile的配置属性，多个profile使用逗号分隔
+	KeyProfilesActive = "gopher.profiles.active"
+	// 激活profile的环境变量，优先级高于配置文件
+	EnvProfilesActive = "GOPHER_PROFILES_ACTIVE"
+	// 导入其他配置文件的配置属性，多个文件使用逗号分隔，相对路径基于当前配置文件所在目录
+	KeyConfigImport = "gopher.config.import"
+)
+
+// 加载yaml配置文件，并将激活的profile对应的配置文件（如application.yaml对应的application-dev.yaml）
+// 按顺序覆盖到基础配置之上。
+// 激活的profile优先使用参数profiles，其次为环境变量GOPHER_PROFILES_ACTIVE，最后为配置文件中的gopher.profiles.active。
+// 配置文件中gopher.config.import导入的文件覆盖到导入它的配置文件之上。
+func LoadYamlFileWithProfiles(path string, profiles ...string) (yfig.Properties, error) {
+	return loadYamlFileWithProfiles(path, profiles, nil)
+}
+
+// files：记录加载的所有配置文件路径，为nil时不记录
+func loadYamlFileWithProfiles(path string, profiles []string, files *[]string) (yfig.Properties, error) {
+	prop, err := loadYamlFileWithImports(path, map[string]bool{}, files)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(profiles) == 0 {
+		if env, ok := os.LookupEnv(EnvProfilesActive); ok {
+			profiles = ParseProfiles(env)
+		} else {
+			profiles = ActiveProfiles(prop)
+		}
+	}
+	if len(profiles) == 0 {
+		return prop, nil
+	}
+
+	value := valueOf(prop)
+	for _, profile := range profiles {
+		profilePath := ProfileFilePath(path, profile)
+		p, err := loadYamlFileWithImports(profilePath, map[string]bool{}, files)
+		if err != nil {
+			if os.IsNotExist(err) {
+				xlog.Warnf("Profile [%s] config file %s not found, skip. ", profile, profilePath)
+				continue
+			}
+			return nil, err
+		}
+		MergeValue(value, valueOf(p))
+	}
+	SetValue(value, KeyProfilesActive, strings.Join(profiles, ","))
+	return NewProperties(value), nil
+}
+
+// 加载yaml配置文件及其导入的配置文件，visited用于检查循环导入
+func loadYamlFileWithImports(path string, visited map[string]bool, files *[]string) (yfig.Properties, error) {
+	prop, err := yfig.LoadYamlFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if files != nil {
+		*files = append(*files, path)
+	}
+	imports := ParseProfiles(prop.Get(KeyConfigImport, ""))
+	if len(imports) == 0 {
+		return prop, nil
+	}
+
+	abs, _ := filepath.Abs(path)
+	visited[abs] = true
+	value := valueOf(prop)
+	for _, i := range imports {
+		if !filepath.IsAbs(i) {
+			i = filepath.Join(filepath.Dir(path), i)
+		}
+		if abs, _ := filepath.Abs(i); visited[abs] {
+			return nil, fmt.Errorf("Config file %s import %s cycle ", path, i)
+		}
+		p, err := loadYamlFileWithImports(i, visited, files)
+		if err != nil {
+			return nil, fmt.Errorf("Config file %s import %s failed: %v ", path, i, err)
+		}
+		MergeValue(value, valueOf(p))
+	}
+	delete(visited, abs)
+	return NewProperties(value), nil
+}
+
+// 获得profile对应的配置文件路径，如：application.yaml -> application-dev.yaml
+func ProfileFilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + profile + ext
+}
+
+// 获得配置中激活的profile
+func ActiveProfiles(prop yfig.Properties) []string {
+	if prop == nil {
+		return nil
+	}
+	return ParseProfiles(prop.Get(KeyProfilesActive, ""))
+}
+
+// 解析profile列表，支持逗号分隔的字符串以及yaml数组（[dev test]）
+func ParseProfiles(s string) []string {
+	s = strings.Trim(strings.TrimSpace(s), "[]")
+	var ret []string
+	for _, p := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	}) {
+		if p != "" {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
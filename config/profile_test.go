@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadYamlFileWithProfiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"application.yaml": `
+gopher:
+  profiles:
+    active: dev
+db:
+  host: localhost
+  port: 3306
+  user: root
+`,
+		"application-dev.yaml": `
+db:
+  host: dev.local
+`,
+		"application-test.yaml": `
+db:
+  port: 3307
+`,
+	})
+	path := filepath.Join(dir, "application.yaml")
+
+	tests := []struct {
+		name     string
+		profiles []string
+		env      string
+		expect   map[string]string
+		active   []string
+	}{
+		{
+			name:   "config active",
+			expect: map[string]string{"db.host": "dev.local", "db.port": "3306", "db.user": "root"},
+			active: []string{"dev"},
+		},
+		{
+			// 后面的profile覆盖前面的profile，map递归合并
+			name:     "explicit profiles",
+			profiles: []string{"dev", "test"},
+			expect:   map[string]string{"db.host": "dev.local", "db.port": "3307", "db.user": "root"},
+			active:   []string{"dev", "test"},
+		},
+		{
+			name:   "env overrides config",
+			env:    "test",
+			expect: map[string]string{"db.host": "localhost", "db.port": "3307"},
+			active: []string{"test"},
+		},
+		{
+			name:     "explicit overrides env",
+			profiles: []string{"dev"},
+			env:      "test",
+			expect:   map[string]string{"db.host": "dev.local", "db.port": "3306"},
+			active:   []string{"dev"},
+		},
+		{
+			// 不存在的profile配置文件被跳过
+			name:     "missing profile file",
+			profiles: []string{"prod"},
+			expect:   map[string]string{"db.host": "localhost", "db.port": "3306"},
+			active:   []string{"prod"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvProfilesActive, tt.env)
+			if tt.env == "" {
+				_ = os.Unsetenv(EnvProfilesActive)
+			}
+			prop, err := LoadYamlFileWithProfiles(path, tt.profiles...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.expect {
+				if got := prop.Get(k, ""); got != v {
+					t.Fatalf("expect %s=%s, got %s", k, v, got)
+				}
+			}
+			if got := ActiveProfiles(prop); !reflect.DeepEqual(got, tt.active) {
+				t.Fatalf("expect active profiles %v, got %v", tt.active, got)
+			}
+		})
+	}
+}
+
+func TestConfigImport(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"application.yaml": `
+gopher:
+  config:
+    import: db.yaml
+db:
+  host: localhost
+`,
+		"db.yaml": `
+db:
+  host: db.local
+  port: 3306
+`,
+		"cycle-a.yaml": `
+gopher:
+  config:
+    import: cycle-b.yaml
+`,
+		"cycle-b.yaml": `
+gopher:
+  config:
+    import: cycle-a.yaml
+`,
+	})
+
+	prop, err := LoadYamlFileWithProfiles(filepath.Join(dir, "application.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 导入的文件覆盖导入它的文件
+	if prop.Get("db.host", "") != "db.local" || prop.Get("db.port", "") != "3306" {
+		t.Fatalf("expect imported values, got %s:%s", prop.Get("db.host", ""), prop.Get("db.port", ""))
+	}
+
+	if _, err := LoadYamlFileWithProfiles(filepath.Join(dir, "cycle-a.yaml")); err == nil {
+		t.Fatal("expect import cycle error")
+	}
+}
+
+func TestParseProfiles(t *testing.T) {
+	tests := []struct {
+		s      string
+		expect []string
+	}{
+		{s: "", expect: nil},
+		{s: "dev", expect: []string{"dev"}},
+		{s: "dev, test", expect: []string{"dev", "test"}},
+		{s: "[dev test]", expect: []string{"dev", "test"}},
+	}
+	for _, tt := range tests {
+		if got := ParseProfiles(tt.s); !reflect.DeepEqual(got, tt.expect) {
+			t.Fatalf("parse %q expect %v, got %v", tt.s, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"fmt"
+	"github.com/ydx1011/yfig"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// 默认的环境变量前缀，带有该前缀的环境变量即使在配置中不存在也会被加载
+	DefaultEnvPrefix = "GOPHER_"
+
+	SourceNameDefaults    = "defaults"
+	SourceNameEnv         = "env"
+	SourceNameCommandLine = "commandLine"
+)
+
+// 配置属性来源
+type PropertySource interface {
+	// 来源名称
+	Name() string
+
+	// 加载属性值
+	// current：优先级更低的来源已合并的属性值，用于环境变量宽松绑定等需要参考已有配置的来源，不应修改
+	Load(current yfig.Value) (yfig.Value, error)
+}
+
+// 按优先级从低到高排列的配置属性来源，优先级高的来源覆盖优先级低的来源中相同的属性
+type PropertySources struct {
+	sources []PropertySource
+}
+
+// 创建配置属性来源链，sources按优先级从低到高排列
+func NewPropertySources(sources ...PropertySource) *PropertySources {
+	return &PropertySources{
+		sources: sources,
+	}
+}
+
+// 添加优先级最高的来源
+func (s *PropertySources) AddLast(source PropertySource) *PropertySources {
+	s.sources = append(s.sources, source)
+	return s
+}
+
+// 添加优先级最低的来源
+func (s *PropertySources) AddFirst(source PropertySource) *PropertySources {
+	s.sources = append([]PropertySource{source}, s.sources...)
+	return s
+}
+
+// 按优先级依次加载所有来源并合并，返回的Properties可以通过Origin查询属性的来源
+func (s *PropertySources) Load() (*SourcedProperties, error) {
+	value := yfig.Value{}
+	origins := map[string]string{}
+	for _, source := range s.sources {
+		v, err := source.Load(value)
+		if err != nil {
+			return nil, fmt.Errorf("Load property source [%s] failed: %v ", source.Name(), err)
+		}
+		MergeValue(value, v)
+		for _, key := range FlattenKeys(v) {
+			origins[key] = source.Name()
+		}
+	}
+	return &SourcedProperties{
+		Properties: NewProperties(value),
+		origins:    origins,
+	}, nil
+}
+
+// 获得所有来源最近一次加载的配置文件路径，用于监听配置文件变化
+func (s *PropertySources) Files() []string {
+	var ret []string
+	for _, source := range s.sources {
+		if f, ok := source.(interface{ Files() []string }); ok {
+			ret = append(ret, f.Files()...)
+		}
+	}
+	return ret
+}
+
+// 记录属性来源的Properties
+type SourcedProperties struct {
+	yfig.Properties
+	origins map[string]string
+}
+
+// 获得提供属性key（完整的叶子节点名称，如gopher.application.name）的来源名称
+func (p *SourcedProperties) Origin(key string) (string, bool) {
+	v, ok := p.origins[key]
+	return v, ok
+}
+
+// 获得属性key的来源名称，prop不记录来源时返回false
+func Origin(prop yfig.Properties, key string) (string, bool) {
+	if p, ok := prop.(*SourcedProperties); ok {
+		return p.Origin(key)
+	}
+	return "", false
+}
+
+// 获得属性值中所有叶子节点的完整名称，按名称排序
+func FlattenKeys(v yfig.Value) []string {
+	values := map[string]interface{}{}
+	flatten("", v, values)
+	ret := make([]string, 0, len(values))
+	for k := range values {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+type defaultsSource struct {
+	values map[string]interface{}
+}
+
+// 代码设置的默认值来源，key为以"."分隔的完整名称，如：gopher.inject.workers
+func NewDefaultsSource(values map[string]interface{}) *defaultsSource {
+	return &defaultsSource{
+		values: values,
+	}
+}
+
+func (s *defaultsSource) Name() string {
+	return SourceNameDefaults
+}
+
+func (s *defaultsSource) Load(current yfig.Value) (yfig.Value, error) {
+	ret := yfig.Value{}
+	for k, v := range s.values {
+		SetValue(ret, k, v)
+	}
+	return ret, nil
+}
+
+type fileSource struct {
+	path     string
+	profiles []string
+
+	files []string
+	lock  sync.Mutex
+}
+
+// yaml配置文件来源，包含激活profile的配置文件及gopher.config.import导入的配置文件，规则见LoadYamlFileWithProfiles
+func NewFileSource(path string, profiles ...string) *fileSource {
+	return &fileSource{
+		path:     path,
+		profiles: profiles,
+	}
+}
+
+func (s *fileSource) Name() string {
+	return "file:" + s.path
+}
+
+func (s *fileSource) Load(current yfig.Value) (yfig.Value, error) {
+	var files []string
+	prop, err := loadYamlFileWithProfiles(s.path, s.profiles, &files)
+	if err != nil {
+		return nil, err
+	}
+	s.lock.Lock()
+	s.files = files
+	s.lock.Unlock()
+	return valueOf(prop), nil
+}
+
+// 获得最近一次加载的所有配置文件路径（包括profile及导入的配置文件）
+func (s *fileSource) Files() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.files
+}
+
+type envSource struct {
+	prefix     string
+	unprefixed bool
+	environ    func() []string
+}
+
+type EnvSourceOpt func(*envSource)
+
+// 环境变量来源，只加载带有prefix前缀的环境变量，使用宽松绑定：
+// 1、已存在的属性转换为大写并将"."、"-"替换为"_"后与环境变量名称（或去掉前缀后的名称）匹配，
+// 如gopher.application.bannerMode对应GOPHER_APPLICATION_BANNERMODE，app.timeout对应GOPHER_APP_TIMEOUT；
+// 2、其他环境变量转换为小写并将"_"替换为"."作为属性名称，如GOPHER_INJECT_WORKERS对应gopher.inject.workers。
+// prefix为空或使用OptEnvMatchUnprefixed时，不带前缀的环境变量按规则1覆盖已存在的属性。
+func NewEnvSource(prefix string, opts ...EnvSourceOpt) *envSource {
+	ret := &envSource{
+		prefix:  prefix,
+		environ: os.Environ,
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+// 不带前缀的环境变量同样覆盖已存在的属性，如APP_TIMEOUT覆盖app.timeout。
+// 注意：系统中与属性名称相同的环境变量（如PATH、HOME）都会覆盖配置
+func OptEnvMatchUnprefixed() EnvSourceOpt {
+	return func(s *envSource) {
+		s.unprefixed = true
+	}
+}
+
+func (s *envSource) Name() string {
+	return SourceNameEnv
+}
+
+func (s *envSource) Load(current yfig.Value) (yfig.Value, error) {
+	known := map[string]string{}
+	for _, key := range FlattenKeys(current) {
+		known[EnvName(key)] = key
+	}
+
+	ret := yfig.Value{}
+	for _, env := range s.environ() {
+		i := strings.Index(env, "=")
+		if i <= 0 {
+			continue
+		}
+		name, v := env[:i], env[i+1:]
+		if s.prefix == "" || !strings.HasPrefix(name, s.prefix) {
+			if s.prefix == "" || s.unprefixed {
+				if key, ok := known[strings.ToUpper(name)]; ok {
+					SetValue(ret, key, parseScalar(v))
+				}
+			}
+			continue
+		}
+		if key, ok := known[strings.ToUpper(name)]; ok {
+			SetValue(ret, key, parseScalar(v))
+		} else if key, ok := known[strings.ToUpper(name[len(s.prefix):])]; ok {
+			SetValue(ret, key, parseScalar(v))
+		} else {
+			SetValue(ret, strings.ToLower(strings.ReplaceAll(name, "_", ".")), parseScalar(v))
+		}
+	}
+	return ret, nil
+}
+
+// 获得属性对应的环境变量名称
+func EnvName(key string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+type commandLineSource struct {
+	args []string
+}
+
+// 命令行参数来源，只解析形如--gopher.application.name=x的参数，其他参数被忽略
+func NewCommandLineSource(args []string) *commandLineSource {
+	return &commandLineSource{
+		args: args,
+	}
+}
+
+func (s *commandLineSource) Name() string {
+	return SourceNameCommandLine
+}
+
+func (s *commandLineSource) Load(current yfig.Value) (yfig.Value, error) {
+	ret := yfig.Value{}
+	props, _ := SplitCommandLineArgs(s.args)
+	for k, v := range props {
+		SetValue(ret, k, parseScalar(v))
+	}
+	return ret, nil
+}
+
+// 从命令行参数中分离形如--gopher.application.name=x的属性参数
+// return：props：属性，rest：其余参数（可继续使用flag解析）
+func SplitCommandLineArgs(args []string) (props map[string]string, rest []string) {
+	props = map[string]string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--") {
+			kv := arg[2:]
+			if i := strings.Index(kv, "="); i > 0 && strings.Contains(kv[:i], ".") {
+				props[kv[:i]] = kv[i+1:]
+				continue
+			}
+		}
+		rest = append(rest, arg)
+	}
+	return props, rest
+}
+
+// 将字符串形式的值按yaml规则转换为对应类型，使数字、布尔值能够正确填充
+func parseScalar(s string) interface{} {
+	var ret interface{}
+	if err := yfig.NewYamlLoader().Deserialize(s, &ret); err != nil || ret == nil {
+		return s
+	}
+	if _, ok := ret.(map[string]interface{}); ok {
+		return s
+	}
+	return ret
+}
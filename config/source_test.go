@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"github.com/ydx1011/yfig"
+	"testing"
+)
+
+func TestEnvSourceLoad(t *testing.T) {
+	current := yfig.Value{}
+	SetValue(current, "app.timeout", 1)
+	SetValue(current, "gopher.application.bannerMode", "on")
+	SetValue(current, "path", "/data")
+	environ := []string{
+		"PATH=/usr/bin",
+		"APP_TIMEOUT=5",
+		"GOPHER_APPLICATION_BANNERMODE=console",
+		"GOPHER_INJECT_WORKERS=4",
+	}
+
+	tests := []struct {
+		name    string
+		prefix  string
+		opts    []EnvSourceOpt
+		environ []string
+		expect  map[string]interface{}
+	}{
+		{
+			name:   "prefixed only",
+			prefix: DefaultEnvPrefix,
+			expect: map[string]interface{}{
+				"gopher.application.bannerMode": "console",
+				"gopher.inject.workers":         4,
+			},
+		},
+		{
+			name:    "strip prefix",
+			prefix:  DefaultEnvPrefix,
+			environ: []string{"GOPHER_APP_TIMEOUT=7", "APP_TIMEOUT=5"},
+			expect: map[string]interface{}{
+				"app.timeout": 7,
+			},
+		},
+		{
+			name:   "unprefixed opt-in",
+			prefix: DefaultEnvPrefix,
+			opts:   []EnvSourceOpt{OptEnvMatchUnprefixed()},
+			expect: map[string]interface{}{
+				"app.timeout":                   5,
+				"path":                          "/usr/bin",
+				"gopher.application.bannerMode": "console",
+				"gopher.inject.workers":         4,
+			},
+		},
+		{
+			name:   "empty prefix",
+			prefix: "",
+			expect: map[string]interface{}{
+				"app.timeout":                   5,
+				"path":                          "/usr/bin",
+				"gopher.application.bannerMode": "console",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewEnvSource(tt.prefix, tt.opts...)
+			s.environ = func() []string {
+				if tt.environ != nil {
+					return tt.environ
+				}
+				return environ
+			}
+			v, err := s.Load(current)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]interface{}{}
+			flatten("", v, got)
+			// 数字的具体类型由yaml解析决定，按字符串形式比较
+			if fmt.Sprint(got) != fmt.Sprint(tt.expect) {
+				t.Fatalf("expect %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestSplitCommandLineArgs(t *testing.T) {
+	props, rest := SplitCommandLineArgs([]string{
+		"--gopher.application.name=demo",
+		"--app.timeout=5",
+		"--verbose",
+		"--name=x",
+		"-beans-graph", "beans.dot",
+	})
+	if fmt.Sprint(props) != "map[app.timeout:5 gopher.application.name:demo]" {
+		t.Fatalf("unexpected props %v", props)
+	}
+	// 不包含"."的参数保留给flag解析
+	if fmt.Sprint(rest) != "[--verbose --name=x -beans-graph beans.dot]" {
+		t.Fatalf("unexpected rest %v", rest)
+	}
+}
+
+// 优先级高的来源覆盖优先级低的来源，并记录每个属性的来源
+func TestPropertySourcesPrecedence(t *testing.T) {
+	env := NewEnvSource(DefaultEnvPrefix)
+	env.environ = func() []string {
+		return []string{"GOPHER_APP_PORT=8081", "GOPHER_APP_HOST=env.local"}
+	}
+	sources := NewPropertySources(
+		env,
+		NewCommandLineSource([]string{"--app.port=9090", "--app.debug=true"}),
+	).AddFirst(NewDefaultsSource(map[string]interface{}{
+		"app.port": 8080,
+		"app.name": "demo",
+		"app.host": "localhost",
+	}))
+	prop, err := sources.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key    string
+		value  string
+		origin string
+	}{
+		{key: "app.name", value: "demo", origin: SourceNameDefaults},
+		{key: "app.host", value: "env.local", origin: SourceNameEnv},
+		{key: "app.port", value: "9090", origin: SourceNameCommandLine},
+		{key: "app.debug", value: "true", origin: SourceNameCommandLine},
+	}
+	for _, tt := range tests {
+		if v := prop.Get(tt.key, ""); v != tt.value {
+			t.Fatalf("expect %s=%s, got %s", tt.key, tt.value, v)
+		}
+		if o, ok := Origin(prop, tt.key); !ok || o != tt.origin {
+			t.Fatalf("expect %s from %s, got %s", tt.key, tt.origin, o)
+		}
+	}
+	// 命令行参数的值按yaml规则转换类型
+	var port int
+	if err := prop.GetValue("app.port", &port); err != nil || port != 9090 {
+		t.Fatalf("expect int port, got %v %v", port, err)
+	}
+}
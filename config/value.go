@@ -0,0 +1,98 @@
+package config
+
+import (
+	"github.com/ydx1011/yfig"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// 将src深度合并到dst，src中的值覆盖dst中相同key的值，map类型的值递归合并
+func MergeValue(dst, src yfig.Value) {
+	for k, v := range src {
+		if sm, ok := v.(map[string]interface{}); ok {
+			if dm, ok := dst[k].(map[string]interface{}); ok {
+				MergeValue(dm, sm)
+				continue
+			}
+			nm := map[string]interface{}{}
+			MergeValue(nm, sm)
+			dst[k] = nm
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// 根据以"."分隔的key设置值，如：SetValue(v, "gopher.profiles.active", "dev")
+func SetValue(v yfig.Value, key string, value interface{}) {
+	keys := strings.Split(key, ".")
+	cur := v
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := cur[k].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			cur[k] = next
+		}
+		cur = next
+	}
+	cur[keys[len(keys)-1]] = value
+}
+
+// 使用已解析的值创建Properties
+func NewProperties(v yfig.Value) yfig.Properties {
+	prop := yfig.New()
+	prop.Env = yfig.GetEnvs()
+	prop.Value = &v
+	return prop
+}
+
+func valueOf(prop yfig.Properties) yfig.Value {
+	if p, ok := prop.(*yfig.DefaultProperties); ok && p.Value != nil {
+		return *p.Value
+	}
+	return yfig.Value{}
+}
+
+// 获得两个配置之间值发生变化（新增、删除或修改）的属性名称，按名称排序
+func Diff(old, new yfig.Properties) []string {
+	ov, nv := allValue(old), allValue(new)
+	oldValues, newValues := map[string]interface{}{}, map[string]interface{}{}
+	flatten("", ov, oldValues)
+	flatten("", nv, newValues)
+
+	var ret []string
+	for k, v := range newValues {
+		if o, ok := oldValues[k]; !ok || !reflect.DeepEqual(o, v) {
+			ret = append(ret, k)
+		}
+	}
+	for k := range oldValues {
+		if _, ok := newValues[k]; !ok {
+			ret = append(ret, k)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func allValue(prop yfig.Properties) yfig.Value {
+	if prop == nil {
+		return yfig.Value{}
+	}
+	var ret map[string]interface{}
+	if err := prop.GetValue("", &ret); err != nil {
+		return valueOf(prop)
+	}
+	return ret
+}
+
+func flatten(prefix string, m map[string]interface{}, out map[string]interface{}) {
+	for k, v := range m {
+		if sub, ok := v.(map[string]interface{}); ok {
+			flatten(prefix+k+".", sub, out)
+		} else {
+			out[prefix+k] = v
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// 轮询配置文件修改时间的间隔，如5s，未配置或为0时不监听配置文件
+	KeyConfigWatchInterval = "gopher.config.watch.interval"
+)
+
+// 通过轮询修改时间监听配置文件的变化
+type FileWatcher struct {
+	interval time.Duration
+	files    func() []string
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// 创建配置文件监听器
+// interval：轮询间隔，files：获得需要监听的文件路径，每次轮询时调用，以便监听重新加载后新增的文件
+func NewFileWatcher(interval time.Duration, files func() []string) *FileWatcher {
+	return &FileWatcher{
+		interval: interval,
+		files:    files,
+		stop:     make(chan struct{}),
+	}
+}
+
+// 启动监听，文件的修改时间或存在状态变化时在监听协程中调用onChange
+func (w *FileWatcher) Start(onChange func()) {
+	modTimes := w.modTimes()
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				cur := w.modTimes()
+				if changed(modTimes, cur) {
+					onChange()
+					// onChange可能重新加载了配置文件，重新获取监听的文件
+					cur = w.modTimes()
+				}
+				modTimes = cur
+			}
+		}
+	}()
+}
+
+// 停止监听
+func (w *FileWatcher) Stop() error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	return nil
+}
+
+func (w *FileWatcher) modTimes() map[string]time.Time {
+	ret := map[string]time.Time{}
+	for _, f := range w.files() {
+		if info, err := os.Stat(f); err == nil {
+			ret[f] = info.ModTime()
+		} else {
+			ret[f] = time.Time{}
+		}
+	}
+	return ret
+}
+
+func changed(old, cur map[string]time.Time) bool {
+	if len(old) != len(cur) {
+		return true
+	}
+	for k, v := range cur {
+		if o, ok := old[k]; !ok || !o.Equal(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package injector
+
+import (
+	"fmt"
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+	"strings"
+)
+
+// 注入名称中限定符的前缀，如inject:"@fast"表示注入包含限定符fast的对象
+const QualifierPrefix = "@"
+
+// 解析注入名称中的限定符，如果名称不是限定符则返回false
+func ParseQualifier(name string) (string, bool) {
+	if strings.HasPrefix(name, QualifierPrefix) {
+		return name[len(QualifierPrefix):], true
+	}
+	return "", false
+}
+
+// 查找容器中类型能够赋值给t的候选对象名称，同一个对象定义只返回一次：
+// 1、qualifier不为空时选择包含该限定符的对象（包括指定名称注册的对象）；
+// 2、qualifier为空时选择以类型名称注册的对象以及标记为首选（bean.SetPrimary）的对象。
+// 容器存在父容器时，本容器中没有候选对象才从父容器中查找。
+func FindCandidates(c bean.Container, t reflect.Type, qualifier string) []string {
+	var ret []string
+	seen := map[bean.Definition]bool{}
+	names := map[string]bool{}
+	for _, level := range bean.ContainerHierarchy(c) {
+		level.Scan(func(key string, value bean.Definition) bool {
+			// 被子容器同名对象覆盖
+			if names[key] {
+				return true
+			}
+			names[key] = true
+			if seen[value] || !value.Type().AssignableTo(t) {
+				return true
+			}
+			info, _ := bean.GetRegisterInfo(c, key)
+			if qualifier != "" {
+				if !info.HasQualifier(qualifier) {
+					return true
+				}
+			} else if key != value.Name() && !info.Primary {
+				// 指定名称注册的对象直接跳过，因为在container.Get未满足，所以认定不是用户想要注入的对象
+				return true
+			}
+			seen[value] = true
+			ret = append(ret, key)
+			return true
+		})
+		if len(ret) > 0 {
+			break
+		}
+	}
+	return ret
+}
+
+// 从候选对象中选择唯一的对象：只有一个候选对象时直接选择，否则选择唯一标记为首选的对象，
+// 仍无法确定时返回列出所有候选对象的错误。
+func SelectCandidate(c bean.Container, t reflect.Type, candidates []string) (string, error) {
+	switch len(candidates) {
+	case 0:
+		return "", newInjectError(ReasonNoCandidate, t,
+			fmt.Sprintf("Inject nothing, cannot find any Implementation: %s", reflection.GetTypeName(t)))
+	case 1:
+		return candidates[0], nil
+	}
+
+	var primaries []string
+	for _, key := range candidates {
+		if info, ok := bean.GetRegisterInfo(c, key); ok && info.Primary {
+			primaries = append(primaries, key)
+		}
+	}
+	if len(primaries) == 1 {
+		return primaries[0], nil
+	}
+	if len(primaries) > 1 {
+		candidates = primaries
+	}
+	err := newInjectError(ReasonMultipleCandidates, t,
+		fmt.Sprintf("Auto Inject bean %s found more than 1 candidates: [%s], mark one with bean.SetPrimary() or select by qualifier ",
+			reflection.GetTypeName(t), strings.Join(candidates, ", ")))
+	err.Candidates = candidates
+	return "", err
+}
+
+func qualifierFilter(c bean.Container, qualifier string) func(key string) bool {
+	return func(key string) bool {
+		info, _ := bean.GetRegisterInfo(c, key)
+		return info.HasQualifier(qualifier)
+	}
+}
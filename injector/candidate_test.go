@@ -0,0 +1,174 @@
+package injector
+
+import (
+	"errors"
+	"github.com/ydx1011/gopher-core/bean"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type cache interface {
+	Name() string
+}
+
+type memCache struct{}
+
+func (c *memCache) Name() string { return "mem" }
+
+type redisCache struct{}
+
+func (c *redisCache) Name() string { return "redis" }
+
+type fileCache struct{}
+
+func (c *fileCache) Name() string { return "file" }
+
+type singleCacheBean struct {
+	C cache `inject:""`
+}
+
+type fastCacheBean struct {
+	C  cache            `inject:"@fast"`
+	CS []cache          `inject:"@cache"`
+	CM map[string]cache `inject:"@cache"`
+}
+
+// 注入对象，必须注入的字段失败时RequiredListener会panic，转换为返回的错误
+func inject(c bean.Container, o interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+	return New().Inject(c, o)
+}
+
+func TestPrimarySelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(c bean.Container)
+		expect   string
+		multiple []string
+	}{
+		{
+			name: "single",
+			register: func(c bean.Container) {
+				_ = c.Register(&memCache{})
+			},
+			expect: "mem",
+		},
+		{
+			name: "primary",
+			register: func(c bean.Container) {
+				_ = c.Register(&memCache{})
+				_ = c.Register(&redisCache{}, bean.SetPrimary())
+				_ = c.Register(&fileCache{})
+			},
+			expect: "redis",
+		},
+		{
+			// 指定名称注册的对象标记为primary时同样参与自动注入
+			name: "named primary",
+			register: func(c bean.Container) {
+				_ = c.RegisterByName("redis", &redisCache{}, bean.SetPrimary())
+			},
+			expect: "redis",
+		},
+		{
+			// 指定名称注册的对象不参与自动注入
+			name: "named ignored",
+			register: func(c bean.Container) {
+				_ = c.Register(&memCache{})
+				_ = c.RegisterByName("redis", &redisCache{})
+			},
+			expect: "mem",
+		},
+		{
+			name: "ambiguous",
+			register: func(c bean.Container) {
+				_ = c.Register(&memCache{})
+				_ = c.Register(&redisCache{})
+			},
+			multiple: []string{"memCache", "redisCache"},
+		},
+		{
+			// 多个primary时只在primary中报告
+			name: "multiple primaries",
+			register: func(c bean.Container) {
+				_ = c.Register(&memCache{}, bean.SetPrimary())
+				_ = c.Register(&redisCache{}, bean.SetPrimary())
+				_ = c.Register(&fileCache{})
+			},
+			multiple: []string{"memCache", "redisCache"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := bean.NewContainer()
+			tt.register(c)
+			o := &singleCacheBean{}
+			err := inject(c, o)
+			if tt.multiple != nil {
+				var ie *InjectError
+				if !errors.As(err, &ie) || ie.Reason != ReasonMultipleCandidates {
+					t.Fatalf("expect multiple candidates error, got %v", err)
+				}
+				if len(ie.Candidates) != len(tt.multiple) {
+					t.Fatalf("expect candidates %v, got %v", tt.multiple, ie.Candidates)
+				}
+				for i, name := range tt.multiple {
+					if !strings.HasSuffix(ie.Candidates[i], name) {
+						t.Fatalf("expect candidates %v, got %v", tt.multiple, ie.Candidates)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.C.Name() != tt.expect {
+				t.Fatalf("expect %s, got %s", tt.expect, o.C.Name())
+			}
+		})
+	}
+}
+
+func TestQualifierSelection(t *testing.T) {
+	c := bean.NewContainer()
+	_ = c.Register(&memCache{}, bean.SetQualifiers("cache"))
+	_ = c.Register(&redisCache{}, bean.SetQualifiers("fast", "cache"))
+	// 指定名称注册的对象也可以通过限定符注入
+	_ = c.RegisterByName("file", &fileCache{}, bean.SetQualifiers("cache"))
+
+	o := &fastCacheBean{}
+	if err := inject(c, o); err != nil {
+		t.Fatal(err)
+	}
+	if o.C.Name() != "redis" {
+		t.Fatalf("expect fast cache redis, got %s", o.C.Name())
+	}
+	var names []string
+	for _, v := range o.CS {
+		names = append(names, v.Name())
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "file,mem,redis" {
+		t.Fatalf("expect all caches in slice, got %v", names)
+	}
+	if len(o.CM) != 3 || o.CM["file"] == nil {
+		t.Fatalf("expect all caches in map by name, got %v", o.CM)
+	}
+}
+
+func TestQualifierNotFound(t *testing.T) {
+	c := bean.NewContainer()
+	_ = c.Register(&memCache{})
+	err := inject(c, &struct {
+		C cache `inject:"@fast"`
+	}{})
+	var ie *InjectError
+	if !errors.As(err, &ie) || ie.Reason != ReasonNoCandidate || ie.Name != "@fast" {
+		t.Fatalf("expect no candidate error with qualifier, got %v", err)
+	}
+}
@@ -92,6 +92,9 @@ func (injector *defaultInjector) CanInjectType(t reflect.Type) bool {
 }
 
 func (injector *defaultInjector) InjectValue(c bean.Container, name string, v reflect.Value) error {
+	if ok, err := injector.injectLazy(c, name, v); ok {
+		return err
+	}
 	t := v.Type()
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -103,10 +106,37 @@ func (injector *defaultInjector) InjectValue(c bean.Container, name string, v re
 		}
 		return actuate(c, name, v)
 	} else {
-		return errors.New("Inject Failed: Value cannot set. ")
+		return newInjectError(ReasonCannotSet, v.Type(), "Inject Failed: Value cannot set. ")
 	}
 }
 
+// 延迟注入（bean.LazyInjectable）只设置解析方法，使用时才从容器中获取对象
+func (injector *defaultInjector) injectLazy(c bean.Container, name string, v reflect.Value) (bool, error) {
+	t := v.Type()
+	if !bean.IsLazyInjectable(t) {
+		return false, nil
+	}
+	if !v.CanSet() {
+		return true, newInjectError(ReasonCannotSet, t, "Inject Failed: Value cannot set. ")
+	}
+	var lazy bean.LazyInjectable
+	if t.Kind() == reflect.Ptr && t.Implements(bean.LazyInjectableType) {
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		lazy = v.Interface().(bean.LazyInjectable)
+	} else {
+		lazy = v.Addr().Interface().(bean.LazyInjectable)
+	}
+	// 延迟注入在使用时获取对象，不属于当前的创建链
+	c = bean.WithCreation(c, nil)
+	lazy.SetResolver(name, func(name string, t reflect.Type) (reflect.Value, error) {
+		o := reflect.New(t).Elem()
+		return o, injector.InjectValue(c, name, o)
+	})
+	return true, nil
+}
+
 type defaultListenerManager struct {
 	listeners sync.Map
 }
@@ -175,8 +205,10 @@ func (l *OmitErrorListener) OnInjectFailed(err error) {
 }
 
 type sliceAppender struct {
+	c        bean.Container
 	v        reflect.Value
 	elemType reflect.Type
+	filter   func(key string) bool
 }
 
 func (s *sliceAppender) Set(value reflect.Value) error {
@@ -185,12 +217,15 @@ func (s *sliceAppender) Set(value reflect.Value) error {
 }
 
 func (s *sliceAppender) Scan(key string, value bean.Definition) bool {
+	if s.filter != nil && !s.filter(key) {
+		return true
+	}
 	ot := value.Type()
 	// interface
 	if ot.AssignableTo(s.elemType) {
-		s.v = reflect.Append(s.v, value.Value())
+		s.v = reflect.Append(s.v, bean.ValueOf(s.c, value))
 	} else if ot.ConvertibleTo(s.elemType) {
-		s.v = reflect.Append(s.v, value.Value().Convert(s.elemType))
+		s.v = reflect.Append(s.v, bean.ValueOf(s.c, value).Convert(s.elemType))
 	}
 	//if s.elemType.Kind() == reflect.Interface {
 	//	if ot.Implements(s.elemType) {
@@ -209,8 +244,10 @@ func (s *sliceAppender) Scan(key string, value bean.Definition) bool {
 }
 
 type mapPutter struct {
+	c        bean.Container
 	v        reflect.Value
 	elemType reflect.Type
+	filter   func(key string) bool
 }
 
 func (s *mapPutter) Set(value reflect.Value) error {
@@ -219,12 +256,15 @@ func (s *mapPutter) Set(value reflect.Value) error {
 }
 
 func (s *mapPutter) Scan(key string, value bean.Definition) bool {
+	if s.filter != nil && !s.filter(key) {
+		return true
+	}
 	ot := value.Type()
 	// interface
 	if ot.AssignableTo(s.elemType) {
-		s.v.SetMapIndex(reflect.ValueOf(key), value.Value())
+		s.v.SetMapIndex(reflect.ValueOf(key), bean.ValueOf(s.c, value))
 	} else if ot.ConvertibleTo(s.elemType) {
-		s.v.SetMapIndex(reflect.ValueOf(key), value.Value().Convert(s.elemType))
+		s.v.SetMapIndex(reflect.ValueOf(key), bean.ValueOf(s.c, value).Convert(s.elemType))
 	}
 	//if s.elemType.Kind() == reflect.Interface {
 	//	if ot.Implements(s.elemType) {
@@ -244,53 +284,102 @@ func (s *mapPutter) Scan(key string, value bean.Definition) bool {
 
 func (injector *defaultInjector) injectInterface(c bean.Container, name string, v reflect.Value) error {
 	vt := v.Type()
+	if qualifier, ok := ParseQualifier(name); ok {
+		return withName(injector.injectCandidate(c, v, FindCandidates(c, vt, qualifier)), name)
+	}
+	explicit := name
 	if name == "" {
 		name = reflection.GetTypeName(vt)
 	}
 	o, ok := c.GetDefinition(name)
 	if ok {
-		v.Set(o.Value())
-		return nil
+		return setValue(v, name, bean.ValueOf(c, o))
 	} else {
 		// 自动注入
-		var matchValues []bean.Definition
-		c.Scan(func(key string, value bean.Definition) bool {
-			// 指定名称注册的对象直接跳过，因为在container.Get未满足，所以认定不是用户想要注入的对象
-			if key != value.Name() {
-				return true
-			}
-			ot := value.Type()
-			if ot.AssignableTo(vt) {
-				matchValues = append(matchValues, value)
-				if len(matchValues) > 1 {
-					panic("Auto Inject bean found more than 1")
-				}
-				return true
-			}
-			return true
-		})
-		if len(matchValues) == 1 {
-			v.Set(matchValues[0].Value())
-			// cache to container
-			err := c.PutDefinition(reflection.GetTypeName(vt), matchValues[0])
-			if err != nil {
-				injector.logger.Warnln(err)
-			}
-			return nil
+		key, err := SelectCandidate(c, vt, FindCandidates(c, vt, ""))
+		if err != nil {
+			return namedMissing(err, explicit)
 		}
+		o, _ = c.GetDefinition(key)
+		if err = setValue(v, key, bean.ValueOf(c, o)); err != nil {
+			return err
+		}
+		// cache to container
+		err = c.PutDefinition(reflection.GetTypeName(vt), o)
+		if err != nil {
+			injector.logger.Warnln(err)
+		}
+		return nil
+	}
+}
+
+func (injector *defaultInjector) injectCandidate(c bean.Container, v reflect.Value, candidates []string) error {
+	key, err := SelectCandidate(c, v.Type(), candidates)
+	if err != nil {
+		return err
 	}
-	return errors.New("Inject nothing, cannot find any Implementation: " + reflection.GetTypeName(vt))
+	o, _ := c.GetDefinition(key)
+	return setValue(v, key, bean.ValueOf(c, o))
+}
+
+// 对象被BeanPostProcessor替换后类型可能发生变化，设置前检查类型
+func setValue(v reflect.Value, name string, ov reflect.Value) error {
+	if !ov.IsValid() {
+		return fmt.Errorf("Inject failed: value of %s is invalid ", reflection.GetTypeName(v.Type()))
+	}
+	if !ov.Type().AssignableTo(v.Type()) {
+		err := newInjectError(ReasonTypeMismatch, v.Type(),
+			fmt.Sprintf("Inject failed: cannot assign %v to %s ", ov.Type(), reflection.GetTypeName(v.Type())))
+		err.Name = name
+		err.Actual = ov.Type()
+		return err
+	}
+	v.Set(ov)
+	return nil
+}
+
+// 为注入错误补充注入名称
+func withName(err error, name string) error {
+	if e, ok := err.(*InjectError); ok && e.Name == "" {
+		e.Name = name
+	}
+	return err
+}
+
+// 指定了注入名称且自动注入也没有找到对象时，失败原因为指定名称的对象不存在
+func namedMissing(err error, name string) error {
+	if e, ok := err.(*InjectError); ok && name != "" && e.Reason == ReasonNoCandidate {
+		e.Reason = ReasonNamedBeanMissing
+		e.Name = name
+	}
+	return err
 }
 
 func (injector *defaultInjector) injectSlice(c bean.Container, name string, v reflect.Value) error {
 	vt := v.Type()
+	if qualifier, ok := ParseQualifier(name); ok {
+		destTmp := sliceAppender{
+			c:        c,
+			v:        v,
+			elemType: vt.Elem(),
+			filter:   qualifierFilter(c, qualifier),
+		}
+		bean.ScanHierarchy(c, destTmp.Scan)
+		destTmp.Set(v)
+		if v.Len() > 0 {
+			return nil
+		}
+		return withName(newInjectError(ReasonNoCandidate, vt,
+			"Slice Inject nothing, cannot find any Implementation with qualifier: "+name), name)
+	}
+	explicit := name
 	if name == "" {
 		name = reflection.GetSliceName(vt)
 	}
 	elemType := vt.Elem()
 	o, ok := c.GetDefinition(name)
 	if ok {
-		dv := o.Value()
+		dv := bean.ValueOf(c, o)
 		n, err := util.SetOrCopySlice(v, dv, true)
 		if n != dv.Len() {
 			injector.logger.Infof("Set slice source have %d elements set %d elements", dv.Len(), n)
@@ -299,10 +388,11 @@ func (injector *defaultInjector) injectSlice(c bean.Container, name string, v re
 	} else {
 		//自动注入
 		destTmp := sliceAppender{
+			c:        c,
 			v:        v,
 			elemType: elemType,
 		}
-		c.Scan(destTmp.Scan)
+		bean.ScanHierarchy(c, destTmp.Scan)
 		destTmp.Set(v)
 		if v.Len() > 0 {
 			// cache to container
@@ -317,19 +407,38 @@ func (injector *defaultInjector) injectSlice(c bean.Container, name string, v re
 			return nil
 		}
 	}
-	return errors.New("Slice Inject nothing, cannot find any Implementation: " + reflection.GetSliceName(vt))
+	return namedMissing(newInjectError(ReasonNoCandidate, vt,
+		"Slice Inject nothing, cannot find any Implementation: "+reflection.GetSliceName(vt)), explicit)
 }
 
 func (injector *defaultInjector) injectMap(c bean.Container, name string, v reflect.Value) error {
 	vt := v.Type()
+	explicit := name
 	if name == "" {
 		name = reflection.GetMapName(vt)
 	}
 	keyType := vt.Key()
 	elemType := vt.Elem()
+	if qualifier, ok := ParseQualifier(name); ok {
+		if keyType.Kind() != reflect.String {
+			return errors.New("Key type must be string. ")
+		}
+		destTmp := mapPutter{
+			c:        c,
+			v:        reflect.MakeMap(vt),
+			elemType: elemType,
+			filter:   qualifierFilter(c, qualifier),
+		}
+		bean.ScanHierarchy(c, destTmp.Scan)
+		if destTmp.v.Len() > 0 {
+			return destTmp.Set(v)
+		}
+		return withName(newInjectError(ReasonNoCandidate, vt,
+			"Map Inject nothing, cannot find any Implementation with qualifier: "+name), name)
+	}
 	o, ok := c.GetDefinition(name)
 	if ok {
-		dv := o.Value()
+		dv := bean.ValueOf(c, o)
 		n, err := util.SetOrCopyMap(v, dv, true)
 		if n != dv.Len() {
 			injector.logger.Infof("Set map source have %d elements set %d elements", dv.Len(), n)
@@ -341,10 +450,11 @@ func (injector *defaultInjector) injectMap(c bean.Container, name string, v refl
 		}
 		//自动注入
 		destTmp := mapPutter{
+			c:        c,
 			v:        v,
 			elemType: elemType,
 		}
-		c.Scan(destTmp.Scan)
+		bean.ScanHierarchy(c, destTmp.Scan)
 		if v.Len() > 0 {
 			// cache to container
 			bean, err := bean.CreateBeanDefinition(v.Interface())
@@ -358,33 +468,42 @@ func (injector *defaultInjector) injectMap(c bean.Container, name string, v refl
 			return nil
 		}
 	}
-	return errors.New("Map Inject nothing, cannot find any Implementation: " + reflection.GetMapName(vt))
+	return namedMissing(newInjectError(ReasonNoCandidate, vt,
+		"Map Inject nothing, cannot find any Implementation: "+reflection.GetMapName(vt)), explicit)
 }
 
 func (injector *defaultInjector) injectStruct(c bean.Container, name string, v reflect.Value) error {
 	vt := v.Type()
+	if qualifier, ok := ParseQualifier(name); ok {
+		if vt.Kind() != reflect.Ptr {
+			return newInjectError(ReasonNotPointer, vt,
+				fmt.Sprintf("Inject struct: [%s] failed: value must be pointer. ", reflection.GetTypeName(vt)))
+		}
+		return withName(injector.injectCandidate(c, v, FindCandidates(c, vt, qualifier)), name)
+	}
+	explicit := name
 	if name == "" {
 		name = reflection.GetTypeName(vt)
 	}
 	o, ok := c.GetDefinition(name)
 	if ok {
-		ov := o.Value()
-		if vt.Kind() == reflect.Ptr {
-			v.Set(ov)
-		} else {
+		ov := bean.ValueOf(c, o)
+		if vt.Kind() != reflect.Ptr {
 			// 只允许注入指针类型
-			err := fmt.Errorf("Inject struct: [%s] failed: value must be pointer. ", reflection.GetTypeName(vt))
+			err := newInjectError(ReasonNotPointer, vt,
+				fmt.Sprintf("Inject struct: [%s] failed: value must be pointer. ", reflection.GetTypeName(vt)))
 			//injector.logger.Errorln(err)
 			return err
 			//v.Set(ov.Elem())
 		}
-		return nil
+		return setValue(v, name, ov)
 	}
 
 	if injector.recursive {
 		return injector.injectStructFields(c, v)
 	} else {
-		return errors.New("Inject nothing, cannot find any instance of  " + reflection.GetTypeName(vt))
+		return namedMissing(newInjectError(ReasonNoCandidate, vt,
+			"Inject nothing, cannot find any instance of  "+reflection.GetTypeName(vt)), explicit)
 	}
 }
 
@@ -406,8 +525,11 @@ func (injector *defaultInjector) injectStructFields(c bean.Container, v reflect.
 			}
 			err := injector.InjectValue(c, tag, fieldValue)
 			if err != nil {
-				err = fmt.Errorf("Inject failed: Field [%s: %s] error: %s\n ",
-					reflection.GetTypeName(t), field.Name, err.Error())
+				err = &FieldInjectError{
+					Owner: t,
+					Field: field.Name,
+					Err:   err,
+				}
 				//injector.logger.Errorln(errStr)
 				for _, l := range listeners {
 					l.OnInjectFailed(err)
@@ -0,0 +1,115 @@
+package injector
+
+import (
+	"github.com/ydx1011/gopher-core/bean"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+	"strings"
+)
+
+// 根据注入方法的参数类型及名称生成注入点，names的规则与InjectFunctionRegistry一致
+func ParseFunctionInjectPoints(function interface{}, names []string) []InjectPoint {
+	ft := reflect.TypeOf(function)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return nil
+	}
+	ret := make([]InjectPoint, ft.NumIn())
+	for i := range ret {
+		ret[i].Type = ft.In(i)
+		if i < len(names) {
+			ret[i].Name = parseInjectName(names[i])
+		}
+	}
+	return ret
+}
+
+func parseInjectName(tag string) string {
+	return strings.Split(tag, ",")[0]
+}
+
+func (injector *defaultInjector) ParseInjectPoints(o interface{}) []InjectPoint {
+	t := reflect.TypeOf(o)
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var ret []InjectPoint
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag, ok := field.Tag.Lookup(injector.tagName); ok {
+			ret = append(ret, InjectPoint{
+				Name: parseInjectName(tag),
+				Type: field.Type,
+			})
+		}
+	}
+	return ret
+}
+
+func (injector *defaultInjector) ResolveInjectPoint(c bean.Container, point InjectPoint) []string {
+	vt := point.Type
+	// 延迟注入不产生依赖
+	if bean.IsLazyInjectable(vt) {
+		return nil
+	}
+	kind := vt.Kind()
+	if kind == reflect.Ptr {
+		kind = vt.Elem().Kind()
+	}
+	name := point.Name
+	qualifier, qualified := ParseQualifier(name)
+	switch kind {
+	case reflect.Interface, reflect.Struct:
+		if qualified {
+			return selectOrAll(c, vt, FindCandidates(c, vt, qualifier))
+		}
+		if name == "" {
+			name = reflection.GetTypeName(vt)
+		}
+		if _, ok := c.GetDefinition(name); ok {
+			return []string{name}
+		}
+		if kind == reflect.Interface {
+			return selectOrAll(c, vt, FindCandidates(c, vt, ""))
+		}
+	case reflect.Slice, reflect.Map:
+		if name == "" {
+			if kind == reflect.Slice {
+				name = reflection.GetSliceName(vt)
+			} else {
+				name = reflection.GetMapName(vt)
+			}
+		}
+		if _, ok := c.GetDefinition(name); ok && !qualified {
+			return []string{name}
+		}
+		elemType := vt.Elem()
+		var ret []string
+		bean.ScanHierarchy(c, func(key string, value bean.Definition) bool {
+			if qualified && !qualifierFilter(c, qualifier)(key) {
+				return true
+			}
+			ot := value.Type()
+			if ot.AssignableTo(elemType) || ot.ConvertibleTo(elemType) {
+				ret = append(ret, key)
+			}
+			return true
+		})
+		return ret
+	}
+	return nil
+}
+
+// 能够确定唯一对象时返回该对象，否则返回所有候选对象（实际注入时会返回错误）
+func selectOrAll(c bean.Container, t reflect.Type, candidates []string) []string {
+	if key, err := SelectCandidate(c, t, candidates); err == nil {
+		return []string{key}
+	}
+	return candidates
+}
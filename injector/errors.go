@@ -0,0 +1,75 @@
+package injector
+
+import (
+	"fmt"
+	"github.com/ydx1011/gopher-core/reflection"
+	"reflect"
+)
+
+// 注入失败的原因
+const (
+	// 容器中没有能够注入的对象
+	ReasonNoCandidate = "noCandidate"
+	// 存在多个候选对象，无法确定注入哪一个
+	ReasonMultipleCandidates = "multipleCandidates"
+	// 注入名称指定的对象不存在
+	ReasonNamedBeanMissing = "namedBeanMissing"
+	// 注入的值不能被设置，如字段未导出
+	ReasonCannotSet = "cannotSet"
+	// 注入的struct不是指针
+	ReasonNotPointer = "notPointer"
+	// 对象类型不能赋值给注入的类型
+	ReasonTypeMismatch = "typeMismatch"
+)
+
+// 注入失败的错误，错误信息与原有的错误描述保持一致，由Reason区分失败原因
+type InjectError struct {
+	// 失败原因
+	Reason string
+
+	// 注入的类型
+	Type reflect.Type
+
+	// 注入名称（tag中指定的名称或限定符），未指定时为空
+	Name string
+
+	// 多个候选对象时的候选对象名称
+	Candidates []string
+
+	// 类型不匹配时对象的实际类型
+	Actual reflect.Type
+
+	msg string
+}
+
+func newInjectError(reason string, t reflect.Type, msg string) *InjectError {
+	return &InjectError{
+		Reason: reason,
+		Type:   t,
+		msg:    msg,
+	}
+}
+
+func (e *InjectError) Error() string {
+	return e.msg
+}
+
+// 字段注入失败的错误
+type FieldInjectError struct {
+	// 字段所属的struct类型
+	Owner reflect.Type
+
+	// 字段名称
+	Field string
+
+	// 失败原因
+	Err error
+}
+
+func (e *FieldInjectError) Error() string {
+	return fmt.Sprintf("Inject failed: Field [%s: %s] error: %s\n ", reflection.GetTypeName(e.Owner), e.Field, e.Err.Error())
+}
+
+func (e *FieldInjectError) Unwrap() error {
+	return e.Err
+}
@@ -32,7 +32,7 @@ func (invoker *defaultInjectInvoker) Invoke(ij Injector, container bean.Containe
 		}
 		err := ij.InjectValue(container, name, o)
 		if err != nil {
-			err = fmt.Errorf("Inject function [%s] failed:error: %s\n", invoker.FunctionName(), err.Error())
+			err = fmt.Errorf("Inject function [%s] failed:error: %w\n", invoker.FunctionName(), err)
 			for _, l := range listeners {
 				l.OnInjectFailed(err)
 			}
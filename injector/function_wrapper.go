@@ -1,7 +1,6 @@
 package injector
 
 import (
-	"errors"
 	"fmt"
 	"github.com/ydx1011/gopher-core/bean"
 	"reflect"
@@ -12,35 +11,17 @@ func WrapBeanFactoryByNameFunc(o interface{}, names []string, container bean.Con
 	if ft.Kind() != reflect.Func {
 		return o, nil
 	}
-	if ft.NumOut() != 1 {
-		return o, fmt.Errorf("Bean Factory function: %s without any return value ", ft.String())
-	}
-
-	rt := ft.Out(0)
-	if rt.Kind() != reflect.Ptr && rt.Kind() != reflect.Interface {
-		return o, fmt.Errorf("Bean Factory function: %s 1st return value must be pointer or interface ", ft.String())
+	if err := bean.VerifyBeanFunction(ft); err != nil {
+		return o, fmt.Errorf("Bean Factory function: %s invalid: %s ", ft.String(), err.Error())
 	}
 	pn := ft.NumIn()
 	if pn > 0 {
 		if pn != len(names) {
 			return o, fmt.Errorf("Bean Factory function: %s have %d params but with %d names, Not match ", ft.String(), pn, len(names))
 		}
-		retFv := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{rt}, false), func(args []reflect.Value) (results []reflect.Value) {
-			fv := reflect.ValueOf(o)
-			values := make([]reflect.Value, pn)
-			for i := 0; i < pn; i++ {
-				o := reflect.New(ft.In(i)).Elem()
-				err := injector.InjectValue(container, names[i], o)
-				if err != nil {
-					err = fmt.Errorf("Inject function [%s] param %d [%s] failed:error: %s\n", ft.String(), i, o.Type().String(), err.Error())
-					panic(err)
-				}
-				values[i] = o
-			}
-
-			return fv.Call(values)
-		})
-		return retFv.Interface(), nil
+		return wrapFactory(o, ft, func(i int) string {
+			return names[i]
+		}, container, injector), nil
 	}
 	return o, nil
 }
@@ -50,32 +31,74 @@ func WrapBeanFactoryFunc(o interface{}, container bean.Container, injector Injec
 	if ft.Kind() != reflect.Func {
 		return o, nil
 	}
-	if ft.NumOut() != 1 {
-		return o, fmt.Errorf("Bean Factory function: %s without any return value ", ft.String())
+	if err := bean.VerifyBeanFunction(ft); err != nil {
+		return o, fmt.Errorf("Bean Factory function: %s invalid: %s ", ft.String(), err.Error())
+	}
+	if ft.NumIn() > 0 {
+		return wrapFactory(o, ft, func(i int) string {
+			return ""
+		}, container, injector), nil
 	}
+	return o, nil
+}
 
-	rt := ft.Out(0)
-	if rt.Kind() != reflect.Ptr && rt.Kind() != reflect.Interface {
-		return o, errors.New("Bean function 1st return value must be pointer or interface. ")
+// 将带参数的创建方法包装为bean.InjectableFunction，返回值与原方法一致。
+// 参数注入失败时，返回值包含error的方法返回该错误，否则panic
+func wrapFactory(o interface{}, ft reflect.Type, name func(i int) string, container bean.Container, injector Injector) interface{} {
+	outs := make([]reflect.Type, ft.NumOut())
+	for i := range outs {
+		outs[i] = ft.Out(i)
 	}
-	pn := ft.NumIn()
-	if pn > 0 {
-		retFv := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{rt}, false), func(args []reflect.Value) (results []reflect.Value) {
-			fv := reflect.ValueOf(o)
-			values := make([]reflect.Value, pn)
-			for i := 0; i < pn; i++ {
-				o := reflect.New(ft.In(i)).Elem()
-				err := injector.InjectValue(container, "", o)
-				if err != nil {
-					err = fmt.Errorf("Inject function [%s] failed:error: %s\n", ft.Name(), err.Error())
-					panic(err)
-				}
-				values[i] = o
-			}
+	ret := &injectableFunction{
+		fv:        reflect.ValueOf(o),
+		ft:        ft,
+		outs:      outs,
+		name:      name,
+		container: container,
+		injector:  injector,
+	}
+	ret.function = reflect.MakeFunc(reflect.FuncOf(nil, outs, false), func(args []reflect.Value) []reflect.Value {
+		return ret.Call(nil)
+	}).Interface()
+	return ret
+}
+
+type injectableFunction struct {
+	fv        reflect.Value
+	ft        reflect.Type
+	outs      []reflect.Type
+	name      func(i int) string
+	container bean.Container
+	injector  Injector
+	function  interface{}
+}
+
+func (f *injectableFunction) Function() interface{} {
+	return f.function
+}
 
-			return fv.Call(values)
-		})
-		return retFv.Interface(), nil
+// 参数从携带创建链的容器中获取
+func (f *injectableFunction) Call(creation *bean.Creation) (results []reflect.Value) {
+	c := bean.WithCreation(f.container, creation)
+	pn := f.ft.NumIn()
+	values := make([]reflect.Value, pn)
+	for i := 0; i < pn; i++ {
+		v := reflect.New(f.ft.In(i)).Elem()
+		err := f.injector.InjectValue(c, f.name(i), v)
+		if err != nil {
+			err = fmt.Errorf("Inject function [%s] param %d [%s] failed:error: %w\n", f.ft.String(), i, v.Type().String(), err)
+			if len(f.outs) == 1 {
+				panic(err)
+			}
+			results = make([]reflect.Value, len(f.outs))
+			for j := range f.outs {
+				results[j] = reflect.Zero(f.outs[j])
+			}
+			results[len(f.outs)-1] = reflect.ValueOf(&err).Elem()
+			return results
+		}
+		values[i] = v
 	}
-	return o, nil
+
+	return f.fv.Call(values)
 }
@@ -27,6 +27,24 @@ type Injector interface {
 	InjectValue(c bean.Container, name string, v reflect.Value) error
 }
 
+// 注入点，描述对象的一个依赖
+type InjectPoint struct {
+	// 指定注入的名称，为空则按类型自动匹配
+	Name string
+
+	// 注入的目标类型
+	Type reflect.Type
+}
+
+// 依赖解析器，在注入之前分析对象的依赖关系
+type DependencyResolver interface {
+	// 解析对象通过tag声明的所有注入点
+	ParseInjectPoints(o interface{}) []InjectPoint
+
+	// 获得容器中能够满足注入点的所有对象名称，规则与InjectValue一致
+	ResolveInjectPoint(c bean.Container, point InjectPoint) []string
+}
+
 type Actuator func(c bean.Container, name string, v reflect.Value) error
 
 // 注入监听器
@@ -23,3 +23,23 @@ type Processor interface {
 	// 资源回收相关操作
 	bean.Disposable
 }
+
+// 支持配置更新的处理器，配置更新时对标记为可刷新（bean.SetRefreshable）的对象重新处理
+type RefreshProcessor interface {
+	// 使用新的配置重新处理对象
+	// conf：更新后的配置属性，o：对象
+	Refresh(conf yfig.Properties, o interface{}) error
+}
+
+// bean后置处理器，注册到ApplicationContext后在每个bean初始化（BeanAfterSet）的前后调用。
+// 返回的对象（如代理、装饰对象）会替换原对象被注入到依赖该bean的其他bean中，返回nil表示不替换。
+// 注意：替换的对象类型与原对象不同时，只能注入到替换对象能够赋值的字段（如其实现的interface）。
+type BeanPostProcessor interface {
+	// 在bean初始化之前调用，返回的对象会传递给后续的处理器，初始化仍作用于原对象
+	// name：bean名称，bean：当前的对象
+	BeforeInitialization(name string, bean interface{}) (interface{}, error)
+
+	// 在bean初始化之后调用
+	// name：bean名称，bean：当前的对象（可能已被之前的处理器替换）
+	AfterInitialization(name string, bean interface{}) (interface{}, error)
+}
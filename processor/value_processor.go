@@ -55,11 +55,21 @@ func (p *ValueProcessor) Init(conf yfig.Properties, container bean.Container) er
 }
 
 func (p *ValueProcessor) Classify(o interface{}) (bool, error) {
+	return true, p.fill(p.conf, o)
+}
+
+// 使用更新后的配置重新填充对象的属性值
+func (p *ValueProcessor) Refresh(conf yfig.Properties, o interface{}) error {
+	p.conf = conf
+	return p.fill(conf, o)
+}
+
+func (p *ValueProcessor) fill(conf yfig.Properties, o interface{}) error {
 	if p.tagName == "" {
-		return true, yfig.Fill(p.conf, o)
+		return yfig.Fill(conf, o)
 	} else {
 		// 内部兼容tag 'fig'
-		return true, yfig.FillExWithTagNames(p.conf, o, false,
+		return yfig.FillExWithTagNames(conf, o, false,
 			[]string{
 				yfig.TagPrefixName,
 				p.tagPxName,
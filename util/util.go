@@ -9,29 +9,55 @@ import (
 )
 
 func HandlerSignal(logger xlog.Logger, closers ...func() error) (err error) {
+	return HandlerSignalWithReload(logger, nil, closers...)
+}
+
+// 与HandlerSignal一致，收到SIGHUP时调用reload（为nil时忽略SIGHUP）
+// 收到退出信号后依次调用closers并等待其完成（closers应自行控制超时，如gopher.application.shutdownTimeout），
+// 等待期间再次收到退出信号时不再等待直接返回
+func HandlerSignalWithReload(logger xlog.Logger, reload func(), closers ...func() error) (err error) {
 	var (
 		ch = make(chan os.Signal, 1)
 	)
 	signal.Notify(ch, syscall.SIGHUP, syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(ch)
 	for {
 		si := <-ch
 		switch si {
 		case syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGINT:
 			time.Sleep(100 * time.Millisecond)
 			xlog.Infof("Got a signal %s, closing...", si.String())
+			done := make(chan error, 1)
 			go func() {
+				var last error
 				for i := range closers {
 					cErr := closers[i]()
 					if cErr != nil {
 						logger.Errorln(cErr)
-						err = cErr
+						last = cErr
 					}
 				}
+				done <- last
 			}()
-			time.Sleep(3 * time.Second)
+		wait:
+			for {
+				select {
+				case err = <-done:
+					break wait
+				case si = <-ch:
+					if si != syscall.SIGHUP {
+						xlog.Warnf("Got a signal %s again, exit without waiting for closing", si.String())
+						break wait
+					}
+				}
+			}
 			xlog.Infof("------ Process exited ------")
 			return
 		case syscall.SIGHUP:
+			if reload != nil {
+				xlog.Infof("Got a signal %s, reloading...", si.String())
+				reload()
+			}
 		default:
 			return
 		}